@@ -3,6 +3,7 @@ package bootstrap
 import (
 	"context"
 	"errors"
+	"flag"
 	"log"
 	"strings"
 	"time"
@@ -11,14 +12,13 @@ import (
 
 	"github.com/robfig/cron/v3"
 
-	"github.com/patriciabonaldy/big_queue/pkg/kafka"
+	"github.com/patriciabonaldy/big_queue/pkg"
 	"github.com/patriciabonaldy/sports-news/internal/business"
 	"github.com/patriciabonaldy/sports-news/internal/platform/genericClient"
 	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
 	"github.com/patriciabonaldy/sports-news/internal/platform/pubsub"
 	"github.com/patriciabonaldy/sports-news/internal/platform/server"
 	"github.com/patriciabonaldy/sports-news/internal/platform/server/handler"
-	"github.com/patriciabonaldy/sports-news/internal/platform/storage/mongo"
 	"github.com/patriciabonaldy/sports-news/internal/providers"
 )
 
@@ -26,56 +26,130 @@ const (
 	lsbnTZ = "Europe/Lisbon"
 )
 
-func Run() error {
+// Run starts the service. The -storage and -queue flags override the
+// backends of the configuration, -storage=memory -queue=inprocess runs a
+// demo without external dependencies.
+func Run(args []string) error {
+	flags := flag.NewFlagSet("sports-news", flag.ContinueOnError)
+	storage := flags.String("storage", "", "storage backend: mongo, postgres, bolt or memory")
+	queue := flags.String("queue", "", "queue of the syncers: kafka or inprocess")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
 	cfg, err := config.New()
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	if *storage != "" {
+		cfg.Storage = *storage
+	}
+
+	if *queue != "" {
+		cfg.Queue = *queue
+	}
+
+	if err = cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
 	logger := logger.New()
 	loc, err := time.LoadLocation(lsbnTZ)
 	if err != nil {
 		loc = time.Local
 	}
 
+	publisher, consumer, deadLetters, err := newQueue(cfg, logger)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	c := cron.New(cron.WithLocation(loc))
-	if err = sync(cfg, c, logger); err != nil {
+	if err = sync(cfg, c, publisher, logger); err != nil {
 		log.Fatal(err)
 	}
 
 	ctx := context.Background()
-	repository, err := mongo.NewDBStorage(ctx, cfg.Database, logger)
+	repository, err := openStorage(ctx, cfg, logger)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	svc := business.NewService(repository, logger)
-	handler := handler.New(svc, logger)
-	ctx, srv := server.New(ctx, cfg, handler)
-
-	err = runBrenfordSubscriber(ctx, cfg, repository, logger)
-	if err != nil {
+	if err = retain(cfg, c, repository, logger); err != nil {
 		log.Fatal(err)
 	}
 
+	pipeline := providers.NewPipeLine(repository, genericClient.New(), cfg.Pipeline, logger)
+	svc := business.NewService(repository, logger)
+	deadLetterSvc := business.NewDeadLetterService(repository, pipeline, logger)
+	srv := server.New(cfg, handler.New(svc, logger), handler.NewDeadLetterHandler(deadLetterSvc, logger))
+
+	subscriber := runNewsSubscriber(ctx, cfg, consumer, deadLetters, pipeline, logger)
+
 	c.Start()
+	srv.Start()
+
+	// the components stop from the sources of work to the storage
+	lc := newLifecycle(time.Duration(cfg.ShutdownTimeout)*time.Second, logger)
+	lc.onStop("cron", func(ctx context.Context) error {
+		select {
+		case <-c.Stop().Done():
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	lc.onStop("http server", srv.Shutdown)
+	lc.onStop("news subscriber", subscriber.Stop)
+	lc.onStop("queue", func(ctx context.Context) error {
+		return closeAll(ctx, consumer, publisher, deadLetters)
+	})
+	lc.onStop("storage", func(ctx context.Context) error {
+		return closeStorage(ctx, repository)
+	})
+
+	return lc.run(ctx)
+}
 
-	return srv.Run(ctx)
+// runNewsSubscriber starts the subscriber that runs the pipeline for the
+// batches of the syncers, it runs until it is stopped.
+func runNewsSubscriber(ctx context.Context, cfg *config.Config, consumer pkg.Consumer, deadLetters pkg.Publisher,
+	pipeline providers.Pipeline, log logger.Logger) newsSubscriber {
+	policy := pubsub.RetryPolicy{
+		Attempts:   cfg.Subscriber.Attempts,
+		Backoff:    time.Duration(cfg.Subscriber.BackoffMillis) * time.Millisecond,
+		MaxBackoff: time.Duration(cfg.Subscriber.MaxBackoffMillis) * time.Millisecond,
+	}
+	subscriber := pubsub.NewSubscriber(consumer, policy, deadLetters, log)
+	pSubscriber := providers.NewNewsSubscriber(pipeline, subscriber, log)
+	pSubscriber.Start(ctx)
+
+	return pSubscriber
 }
 
-func runBrenfordSubscriber(ctx context.Context, cfg *config.Config, repository *mongo.Repository, log logger.Logger) error {
-	if cfg.Kafka.Topic == "" {
-		log.Info("topic-id was not configured")
-		return errors.New("topic-id was not configured")
+// newsSubscriber is the subscriber of the batches of the syncers.
+type newsSubscriber interface {
+	Stop(ctx context.Context) error
+}
+
+// newQueue returns the publisher of the syncers, the consumer of the
+// news subscriber and the publisher of its dead letters of the configured
+// queue. The inprocess queue has no dead-letter topic, the messages that
+// keep failing are dropped.
+func newQueue(cfg *config.Config, log logger.Logger) (pubsub.Publisher, pubsub.Consumer, pubsub.Publisher, error) {
+	if cfg.Queue == config.QueueInProcess {
+		queue := pubsub.NewQueue()
+		return queue, queue, nil, nil
 	}
 
-	client := genericClient.New()
-	pipeline := providers.NewPipeLine(repository, client, log)
-	consumer := kafka.NewConsumer(strings.Split(cfg.Kafka.Broker, ","), cfg.Kafka.Topic)
-	subscriber := pubsub.NewSubscriber(consumer, log)
-	pSubscriber := providers.NewBrenfordSubscriber(pipeline, subscriber, log)
+	if cfg.Kafka == nil || cfg.Kafka.Topic == "" {
+		log.Info("topic-id was not configured")
+		return nil, nil, nil, errors.New("topic-id was not configured")
+	}
 
-	go pSubscriber.Start(ctx)
+	brokers := strings.Split(cfg.Kafka.Broker, ",")
 
-	return nil
+	return pubsub.NewKafkaPublisher(brokers, cfg.Kafka.Topic), pubsub.NewKafkaConsumer(brokers, cfg.Kafka.Topic),
+		pubsub.NewKafkaPublisher(brokers, cfg.Kafka.DeadLetterTopic), nil
 }
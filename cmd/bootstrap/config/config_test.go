@@ -0,0 +1,289 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	cfg, err := New()
+	require.NoError(t, err)
+	assert.NotEmpty(t, cfg.Providers)
+}
+
+func Test_parse_shutdownTimeout(t *testing.T) {
+	got, err := parse([]byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultShutdownTimeout, got.ShutdownTimeout)
+
+	got, err = parse([]byte(`{"shutdown_timeout":30}`))
+	require.NoError(t, err)
+	assert.Equal(t, 30, got.ShutdownTimeout)
+
+	_, err = parse([]byte(`{"shutdown_timeout":-1}`))
+	assert.Error(t, err)
+}
+
+func Test_parse_providers(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Provider
+		wantErr bool
+	}{
+		{
+			name: "default schedule",
+			data: `{"providers":[{"name":"brentford","parser":"incrowd","list_url":"https://list","article_url":"https://article?id=%s"}]}`,
+			want: []Provider{{
+				Name:       "brentford",
+				Parser:     "incrowd",
+				ListURL:    "https://list",
+				ArticleURL: "https://article?id=%s",
+				Schedule:   DefaultSchedule,
+			}},
+		},
+		{
+			name:    "missing parser",
+			data:    `{"providers":[{"name":"brentford","list_url":"https://list"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "article url without id",
+			data:    `{"providers":[{"name":"brentford","parser":"incrowd","list_url":"https://list","article_url":"https://article"}]}`,
+			wantErr: true,
+		},
+		{
+			name: "duplicated name",
+			data: `{"providers":[{"name":"brentford","parser":"incrowd","list_url":"https://list"},
+				{"name":"brentford","parser":"incrowd","list_url":"https://list"}]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parse([]byte(tt.data))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Providers)
+		})
+	}
+}
+
+func Test_parse_retention(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		want        Retention
+		wantEnabled bool
+		wantErr     bool
+	}{
+		{
+			name: "no retention",
+			data: `{}`,
+			want: Retention{Schedule: DefaultRetentionSchedule},
+		},
+		{
+			name: "per club",
+			data: `{"retention":{"schedule":"0 4 * * *","clubs":{"Brentford":180},"export_dir":"/var/archive"}}`,
+			want: Retention{
+				Schedule:  "0 4 * * *",
+				Clubs:     map[string]int{"Brentford": 180},
+				ExportDir: "/var/archive",
+			},
+			wantEnabled: true,
+		},
+		{
+			name:    "negative days",
+			data:    `{"retention":{"clubs":{"Brentford":-1}}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parse([]byte(tt.data))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Retention)
+			assert.Equal(t, tt.wantEnabled, got.Retention.Enabled())
+		})
+	}
+}
+
+func Test_parse_storage(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "default storage",
+			data: `{}`,
+			want: StorageMongo,
+		},
+		{
+			name: "postgres",
+			data: `{"storage":"postgres","postgres":{"dsn":"postgres://localhost/news"}}`,
+			want: StoragePostgres,
+		},
+		{
+			name:    "postgres without dsn",
+			data:    `{"storage":"postgres"}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown storage",
+			data:    `{"storage":"cassandra"}`,
+			wantErr: true,
+		},
+		{
+			name: "bolt",
+			data: `{"storage":"bolt","bolt":{"path":"news.db"}}`,
+			want: StorageBolt,
+		},
+		{
+			name:    "bolt without path",
+			data:    `{"storage":"bolt"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parse([]byte(tt.data))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Storage)
+		})
+	}
+}
+
+func Test_parse_queue(t *testing.T) {
+	got, err := parse([]byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, QueueKafka, got.Queue)
+
+	got, err = parse([]byte(`{"queue":"inprocess"}`))
+	require.NoError(t, err)
+	assert.Equal(t, QueueInProcess, got.Queue)
+
+	_, err = parse([]byte(`{"queue":"rabbitmq"}`))
+	assert.Error(t, err)
+
+	got, err = parse([]byte(`{"kafka":{"topic":"sportsnews"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "sportsnews-dead-letter", got.Kafka.DeadLetterTopic)
+}
+
+func Test_parse_pipeline(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Pipeline
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			data: `{}`,
+			want: Pipeline{
+				Workers:       DefaultWorkers,
+				RatePerHost:   DefaultRatePerHost,
+				QueueSize:     DefaultQueueSize,
+				Attempts:      DefaultAttempts,
+				BackoffMillis: DefaultBackoff,
+			},
+		},
+		{
+			name: "configured",
+			data: `{"pipeline":{"workers":2,"rate_per_host":0.5,"queue_size":4,"attempts":5,"backoff_ms":100}}`,
+			want: Pipeline{Workers: 2, RatePerHost: 0.5, QueueSize: 4, Attempts: 5, BackoffMillis: 100},
+		},
+		{
+			name: "unlimited rate without retries",
+			data: `{"pipeline":{"rate_per_host":-1,"attempts":1}}`,
+			want: Pipeline{
+				Workers:       DefaultWorkers,
+				RatePerHost:   -1,
+				QueueSize:     DefaultQueueSize,
+				Attempts:      1,
+				BackoffMillis: DefaultBackoff,
+			},
+		},
+		{
+			name:    "negative workers",
+			data:    `{"pipeline":{"workers":-1}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parse([]byte(tt.data))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Pipeline)
+		})
+	}
+}
+
+func Test_parse_subscriber(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Subscriber
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			data: `{}`,
+			want: Subscriber{
+				Attempts:         DefaultSubscriberAttempts,
+				BackoffMillis:    DefaultSubscriberBackoff,
+				MaxBackoffMillis: DefaultSubscriberMaxBackoff,
+			},
+		},
+		{
+			name: "configured",
+			data: `{"subscriber":{"attempts":5,"backoff_ms":100,"max_backoff_ms":1000}}`,
+			want: Subscriber{Attempts: 5, BackoffMillis: 100, MaxBackoffMillis: 1000},
+		},
+		{
+			name:    "negative attempts",
+			data:    `{"subscriber":{"attempts":-1}}`,
+			wantErr: true,
+		},
+		{
+			name:    "max backoff less than backoff",
+			data:    `{"subscriber":{"backoff_ms":2000,"max_backoff_ms":1000}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parse([]byte(tt.data))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Subscriber)
+		})
+	}
+}
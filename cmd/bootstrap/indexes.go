@@ -0,0 +1,61 @@
+package bootstrap
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/mongo"
+)
+
+// Indexes runs the indexes command, it prints how the indexes of the
+// database compare to the declared ones. With -create the missing ones
+// are created first.
+func Indexes(args []string) error {
+	flags := flag.NewFlagSet("indexes", flag.ContinueOnError)
+	create := flags.Bool("create", false, "create the missing indexes")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	repository, err := openMongo(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *create {
+		if err = repository.EnsureIndexes(ctx); err != nil {
+			return err
+		}
+	}
+
+	report, err := repository.IndexReport(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COLLECTION\tINDEX\tSTATE")
+	var drift int
+	for _, s := range report {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Collection, s.Name, s.State)
+		if s.State != mongo.IndexOK {
+			drift++
+		}
+	}
+
+	if err = w.Flush(); err != nil {
+		return err
+	}
+
+	if drift > 0 {
+		return errors.Errorf("%d indexes differ from the declared ones", drift)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package bootstrap
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+)
+
+// stopper stops a component of the service, it returns once the
+// component stopped or the context is done.
+type stopper struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// lifecycle stops the components of the service in the order they were
+// registered once it receives SIGINT or SIGTERM, all of them within the
+// shutdown timeout. A second signal stops the process at once.
+type lifecycle struct {
+	timeout  time.Duration
+	stoppers []stopper
+	log      logger.Logger
+}
+
+func newLifecycle(timeout time.Duration, log logger.Logger) *lifecycle {
+	return &lifecycle{timeout: timeout, log: log}
+}
+
+// onStop registers the stop function of a component.
+func (l *lifecycle) onStop(name string, stop func(ctx context.Context) error) {
+	l.stoppers = append(l.stoppers, stopper{name: name, stop: stop})
+}
+
+// run blocks until a signal arrives or the context is done, and then
+// stops the components. A component that fails to stop does not keep the
+// next ones running, their errors are returned together.
+func (l *lifecycle) run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	<-ctx.Done()
+	stop()
+
+	l.log.Infof("shutting down, %s to stop", l.timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	var failed []string
+	for _, s := range l.stoppers {
+		if err := s.stop(ctx); err != nil {
+			l.log.Errorf("error stopping %s: %s", s.name, err.Error())
+			failed = append(failed, s.name+": "+err.Error())
+			continue
+		}
+
+		l.log.Infof("%s stopped", s.name)
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf("shutdown: %s", strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+// closeAll closes the closers that are not nil, like the kafka consumer
+// that commits its offsets when it is closed, or returns the error of the
+// context when it is done first.
+func closeAll(ctx context.Context, closers ...io.Closer) error {
+	done := make(chan error, 1)
+	go func() {
+		var failed []string
+		for _, closer := range closers {
+			if closer == nil {
+				continue
+			}
+
+			if err := closer.Close(); err != nil {
+				failed = append(failed, err.Error())
+			}
+		}
+
+		if len(failed) > 0 {
+			done <- errors.New(strings.Join(failed, "; "))
+			return
+		}
+
+		done <- nil
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,68 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/pubsub"
+)
+
+func Test_lifecycle_run(t *testing.T) {
+	var stopped []string
+	stopFunc := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			stopped = append(stopped, name)
+			return err
+		}
+	}
+
+	lc := newLifecycle(time.Second, logger.New())
+	lc.onStop("cron", stopFunc("cron", nil))
+	lc.onStop("news subscriber", func(ctx context.Context) error {
+		stopped = append(stopped, "news subscriber")
+		// a batch that outlives the shutdown timeout
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	lc.onStop("storage", stopFunc("storage", errors.New("connection reset")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := lc.run(ctx)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.EqualError(t, err, "shutdown: news subscriber: context deadline exceeded; storage: connection reset")
+	assert.Equal(t, []string{"cron", "news subscriber", "storage"}, stopped)
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+func Test_closeAll(t *testing.T) {
+	var committed bool
+	consumer := closerFunc(func() error {
+		committed = true
+		return nil
+	})
+	publisher := closerFunc(func() error { return errors.New("broker not available") })
+	blocked := closerFunc(func() error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	// the in-process queue has no dead-letter publisher
+	var deadLetters pubsub.Publisher
+	assert.EqualError(t, closeAll(context.Background(), consumer, publisher, deadLetters), "broker not available")
+	assert.True(t, committed)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, closeAll(ctx, blocked), context.DeadlineExceeded)
+}
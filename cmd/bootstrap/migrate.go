@@ -0,0 +1,78 @@
+package bootstrap
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Migrate runs the migrate command: up applies the pending migrations,
+// down reverts the last -steps ones and status lists them.
+func Migrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	steps := flags.Int("steps", 1, "number of migrations to revert with down")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("usage: migrate [-steps n] up|down|status")
+	}
+
+	ctx := context.Background()
+	migrator, err := openMigrator(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch action := flags.Arg(0); action {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		printMigrations("applied", applied)
+		return err
+	case "down":
+		if *steps < 1 {
+			return errors.New("steps must be greater than 0")
+		}
+
+		reverted, err := migrator.Down(ctx, *steps)
+		printMigrations("reverted", reverted)
+		return err
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range status {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+
+		return w.Flush()
+	default:
+		return errors.Errorf("unknown migrate action %q", action)
+	}
+}
+
+func printMigrations(action string, migrations []migration) {
+	if len(migrations) == 0 {
+		fmt.Printf("no migrations %s\n", action)
+		return
+	}
+
+	for _, m := range migrations {
+		fmt.Printf("%s %d %s\n", action, m.Version, m.Name)
+	}
+}
@@ -0,0 +1,46 @@
+package bootstrap
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/patriciabonaldy/big_queue/pkg/kafka"
+	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/pubsub"
+)
+
+// Replay runs the replay command, it publishes the messages of the
+// dead-letter topic to the topic of the news subscriber again. It stops
+// after -limit messages, or once no message arrives for -idle.
+func Replay(args []string) error {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	limit := flags.Int("limit", 0, "maximum number of messages to replay, 0 replays every message")
+	idle := flags.Duration("idle", 10*time.Second, "stop once no message arrives for this long")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Queue != config.QueueKafka || cfg.Kafka == nil || cfg.Kafka.Topic == "" {
+		return errors.Errorf("%s queue: the command only applies to the kafka queue", cfg.Queue)
+	}
+
+	brokers := strings.Split(cfg.Kafka.Broker, ",")
+	consumer := kafka.NewConsumer(brokers, cfg.Kafka.DeadLetterTopic)
+	publisher := kafka.NewPublisher(brokers, cfg.Kafka.Topic)
+
+	replayed, err := pubsub.Replay(context.Background(), consumer, publisher, *limit, *idle, logger.New())
+	fmt.Printf("%d messages replayed from %s to %s\n", replayed, cfg.Kafka.DeadLetterTopic, cfg.Kafka.Topic)
+
+	return err
+}
@@ -0,0 +1,36 @@
+package bootstrap
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+
+	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/retention"
+)
+
+// retain schedules the retention job next to the syncers when a
+// retention period is configured.
+func retain(cfg *config.Config, cron *cron.Cron, repository internal.Storage, log logger.Logger) error {
+	if !cfg.Retention.Enabled() {
+		log.Info("retention was not configured, articles are kept forever")
+		return nil
+	}
+
+	job := retention.NewJob(repository, cfg.Retention, log)
+	log.Info("retention", cfg.Retention.Schedule)
+	_, err := cron.AddFunc(cfg.Retention.Schedule, func() {
+		archived, err := job.Run(context.Background(), time.Now().UTC())
+		if err != nil {
+			log.Error(err)
+		}
+
+		log.Infof("retention finished: archived=%d", archived)
+	})
+
+	return errors.Wrap(err, "retention")
+}
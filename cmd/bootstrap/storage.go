@@ -0,0 +1,205 @@
+package bootstrap
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/bolt"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/memory"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/mongo"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/postgres"
+)
+
+// migration is a migration of any storage backend, AppliedAt is nil
+// for pending migrations.
+type migration struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// migrator is the Migrator of the configured storage backend.
+type migrator interface {
+	Up(ctx context.Context) ([]migration, error)
+	Down(ctx context.Context, steps int) ([]migration, error)
+	Status(ctx context.Context) ([]migration, error)
+}
+
+// openStorage connects to the configured storage backend and brings its
+// schema up to date.
+func openStorage(ctx context.Context, cfg *config.Config, log logger.Logger) (internal.Storage, error) {
+	repository, migrator, err := open(ctx, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range applied {
+		log.Infof("migration %d %s applied", m.Version, m.Name)
+	}
+
+	// the postgres indexes are created by its migrations
+	if mongoRepository, ok := repository.(*mongo.Repository); ok {
+		if err = mongoRepository.EnsureIndexes(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return repository, nil
+}
+
+// closeStorage disconnects from the storage backend, the mongo client
+// waits for the operations in progress until the context is done.
+func closeStorage(ctx context.Context, repository internal.Storage) error {
+	switch r := repository.(type) {
+	case *mongo.Repository:
+		return r.Close(ctx)
+	case io.Closer:
+		return r.Close()
+	default:
+		return nil
+	}
+}
+
+// open connects to the storage backend selected by cfg.Storage.
+func open(ctx context.Context, cfg *config.Config, log logger.Logger) (internal.Storage, migrator, error) {
+	switch cfg.Storage {
+	case config.StoragePostgres:
+		repository, err := postgres.NewDBStorage(ctx, cfg.Postgres, log)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return repository, postgresMigrator{repository.Migrator()}, nil
+	case config.StorageBolt:
+		repository, err := bolt.NewDBStorage(cfg.Bolt, log)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return repository, noMigrations{}, nil
+	case config.StorageMemory:
+		return memory.NewStorage(), noMigrations{}, nil
+	default:
+		repository, err := mongo.NewDBStorage(ctx, cfg.Database, log)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return repository, mongoMigrator{repository.Migrator()}, nil
+	}
+}
+
+// openMigrator connects to the configured storage backend, for the
+// migrate command.
+func openMigrator(ctx context.Context) (migrator, error) {
+	cfg, err := config.New()
+	if err != nil {
+		return nil, err
+	}
+
+	_, migrator, err := open(ctx, cfg, logger.New())
+
+	return migrator, err
+}
+
+// openMongo connects to the configured mongo database, for the commands
+// that only apply to it.
+func openMongo(ctx context.Context) (*mongo.Repository, error) {
+	cfg, err := config.New()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Storage != config.StorageMongo {
+		return nil, errors.Errorf("%s storage: the command only applies to the mongo storage", cfg.Storage)
+	}
+
+	return mongo.NewDBStorage(ctx, cfg.Database, logger.New())
+}
+
+type mongoMigrator struct {
+	*mongo.Migrator
+}
+
+func (m mongoMigrator) Up(ctx context.Context) ([]migration, error) {
+	applied, err := m.Migrator.Up(ctx)
+	return fromMongo(applied), err
+}
+
+func (m mongoMigrator) Down(ctx context.Context, steps int) ([]migration, error) {
+	reverted, err := m.Migrator.Down(ctx, steps)
+	return fromMongo(reverted), err
+}
+
+func (m mongoMigrator) Status(ctx context.Context) ([]migration, error) {
+	status, err := m.Migrator.Status(ctx)
+	migrations := make([]migration, 0, len(status))
+	for _, s := range status {
+		migrations = append(migrations, migration{Version: s.Version, Name: s.Name, AppliedAt: s.AppliedAt})
+	}
+
+	return migrations, err
+}
+
+func fromMongo(list []mongo.Migration) []migration {
+	migrations := make([]migration, 0, len(list))
+	for _, m := range list {
+		migrations = append(migrations, migration{Version: m.Version, Name: m.Name})
+	}
+
+	return migrations
+}
+
+type postgresMigrator struct {
+	*postgres.Migrator
+}
+
+func (m postgresMigrator) Up(ctx context.Context) ([]migration, error) {
+	applied, err := m.Migrator.Up(ctx)
+	return fromPostgres(applied), err
+}
+
+func (m postgresMigrator) Down(ctx context.Context, steps int) ([]migration, error) {
+	reverted, err := m.Migrator.Down(ctx, steps)
+	return fromPostgres(reverted), err
+}
+
+func (m postgresMigrator) Status(ctx context.Context) ([]migration, error) {
+	status, err := m.Migrator.Status(ctx)
+	migrations := make([]migration, 0, len(status))
+	for _, s := range status {
+		migrations = append(migrations, migration{Version: s.Version, Name: s.Name, AppliedAt: s.AppliedAt})
+	}
+
+	return migrations, err
+}
+
+func fromPostgres(list []postgres.Migration) []migration {
+	migrations := make([]migration, 0, len(list))
+	for _, m := range list {
+		migrations = append(migrations, migration{Version: m.Version, Name: m.Name})
+	}
+
+	return migrations
+}
+
+// noMigrations is the migrator of the storages that store the articles
+// as they are, without a schema.
+type noMigrations struct{}
+
+func (noMigrations) Up(context.Context) ([]migration, error) { return nil, nil }
+
+func (noMigrations) Down(context.Context, int) ([]migration, error) { return nil, nil }
+
+func (noMigrations) Status(context.Context) ([]migration, error) { return nil, nil }
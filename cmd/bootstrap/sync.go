@@ -2,47 +2,55 @@ package bootstrap
 
 import (
 	"context"
-	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/robfig/cron/v3"
 
-	"github.com/patriciabonaldy/big_queue/pkg/kafka"
+	"github.com/patriciabonaldy/big_queue/pkg"
 	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
 	"github.com/patriciabonaldy/sports-news/internal/platform/genericClient"
 	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
 	"github.com/patriciabonaldy/sports-news/internal/platform/pubsub"
-	"github.com/patriciabonaldy/sports-news/internal/platform/syncer/brentfordFC"
+	"github.com/patriciabonaldy/sports-news/internal/platform/syncer"
+	"github.com/patriciabonaldy/sports-news/internal/platform/syncer/feed"
+	"github.com/patriciabonaldy/sports-news/internal/platform/syncer/incrowd"
 )
 
-const (
-	newsSyncCronSpec = "* * * * *"
-)
+// newRegistry returns the syncers that providers can choose as parser.
+func newRegistry() *syncer.Registry {
+	registry := syncer.NewRegistry()
+	registry.Register("incrowd", incrowd.NewSyncer)
+	registry.Register("rss", feed.NewSyncer)
+	registry.Register("atom", feed.NewSyncer)
+
+	return registry
+}
+
+// sync schedules one syncer per configured provider, they publish the
+// batches of articles with publisher.
+func sync(cfg *config.Config, cron *cron.Cron, publisher pkg.Publisher, log logger.Logger) error {
+	registry := newRegistry()
+	producer := pubsub.NewProducer(publisher)
+	client := genericClient.New()
+	for _, provider := range cfg.Providers {
+		s, err := registry.New(provider, client, producer, log)
+		if err != nil {
+			return err
+		}
 
-func sync(cfg *config.Config, cron *cron.Cron, log logger.Logger) error {
-	log.Info("sync", "provider news")
-	if _, err := cron.AddFunc(newsSyncCronSpec, providerNews(cfg, log)); err != nil {
-		return errors.WithStack(err)
+		log.Info("sync", provider.Name, provider.Schedule)
+		if _, err := cron.AddFunc(provider.Schedule, providerNews(provider, s, log)); err != nil {
+			return errors.Wrapf(err, "provider %s", provider.Name)
+		}
 	}
 
 	return nil
 }
 
-func providerNews(cfg *config.Config, log logger.Logger) func() {
+func providerNews(provider config.Provider, s syncer.Syncer, log logger.Logger) func() {
 	return func() {
-		log.Info("synchronizing")
-		if cfg.Kafka.Topic == "" {
-			log.Info("topic-id was not configured")
-			return
-		}
-
-		ctx := context.Background()
-		publisher := kafka.NewPublisher(strings.Split(cfg.Kafka.Broker, ","), cfg.Kafka.Topic)
-		producer := pubsub.NewProducer(publisher)
-		client := genericClient.New()
-
-		s := brentfordFC.NewSyncer(client, producer, log, cfg)
-		if err := s.Sync(ctx); err != nil {
+		log.Infof("synchronizing %s", provider.Name)
+		if err := s.Sync(context.Background()); err != nil {
 			log.Error(err)
 		}
 	}
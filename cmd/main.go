@@ -2,12 +2,33 @@ package main
 
 import (
 	"log"
+	"os"
+	"strings"
 
 	"github.com/patriciabonaldy/sports-news/cmd/bootstrap"
 )
 
+var commands = map[string]func(args []string) error{
+	"migrate": bootstrap.Migrate,
+	"indexes": bootstrap.Indexes,
+	"replay":  bootstrap.Replay,
+}
+
 func main() {
-	if err := bootstrap.Run(); err != nil {
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		command, ok := commands[os.Args[1]]
+		if !ok {
+			log.Fatalf("unknown command %q", os.Args[1])
+		}
+
+		if err := command(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	if err := bootstrap.Run(os.Args[1:]); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,26 @@
+package internal
+
+import "time"
+
+// ArchiveQuery selects the articles moved to the archive by the retention
+// policy: the ones published before Before, of ClubName or, when it is
+// empty, of every club but ExceptClubs.
+type ArchiveQuery struct {
+	ClubName    string
+	ExceptClubs []string
+	Before      time.Time
+	At          time.Time
+}
+
+// Matches reports whether the article is selected by the query.
+func (q ArchiveQuery) Matches(article ArticleNews) bool {
+	if q.ClubName != "" && article.ClubName != q.ClubName {
+		return false
+	}
+
+	if q.ClubName == "" && contains(q.ExceptClubs, article.ClubName) {
+		return false
+	}
+
+	return !article.PublishDate.IsZero() && article.PublishDate.Before(q.Before)
+}
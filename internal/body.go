@@ -0,0 +1,308 @@
+package internal
+
+import (
+	"errors"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidFormat is returned for unsupported body formats.
+var ErrInvalidFormat = errors.New("invalid format")
+
+// BodyFormat is a representation of a Body.
+type BodyFormat string
+
+const (
+	FormatHTML     BodyFormat = "html"
+	FormatMarkdown BodyFormat = "markdown"
+	FormatText     BodyFormat = "text"
+)
+
+// ParseBodyFormat checks that value is one of the supported formats.
+func ParseBodyFormat(value string) (BodyFormat, error) {
+	switch f := BodyFormat(strings.ToLower(value)); f {
+	case FormatHTML, FormatMarkdown, FormatText:
+		return f, nil
+	default:
+		return "", ErrInvalidFormat
+	}
+}
+
+// BlockType is the kind of a Block of a Body.
+type BlockType string
+
+const (
+	BlockParagraph BlockType = "paragraph"
+	BlockHeading   BlockType = "heading"
+	BlockQuote     BlockType = "quote"
+	BlockImage     BlockType = "image"
+	BlockEmbed     BlockType = "embed"
+)
+
+// InlineType is the kind of an Inline of a Block.
+type InlineType string
+
+const (
+	InlineText      InlineType = "text"
+	InlineLink      InlineType = "link"
+	InlineEmphasis  InlineType = "emphasis"
+	InlineStrong    InlineType = "strong"
+	InlineLineBreak InlineType = "break"
+)
+
+// Body is the rich text of an article, a sequence of blocks.
+type Body struct {
+	Blocks []Block `json:"blocks"`
+}
+
+// Block is a paragraph, heading or quote made of inlines, or an image
+// or an embedded player pointing to Src.
+type Block struct {
+	Type    BlockType `json:"type"`
+	Level   int       `json:"level,omitempty"`
+	Inlines []Inline  `json:"inlines,omitempty"`
+	Src     string    `json:"src,omitempty"`
+	Alt     string    `json:"alt,omitempty"`
+}
+
+// Inline is a run of text, or a link, emphasis or strong wrapping
+// other inlines.
+type Inline struct {
+	Type     InlineType `json:"type"`
+	Text     string     `json:"text,omitempty"`
+	Href     string     `json:"href,omitempty"`
+	Children []Inline   `json:"children,omitempty"`
+}
+
+// TextBody returns a body with a paragraph per block of text, for
+// articles stored before they had a Body.
+func TextBody(text string) Body {
+	var body Body
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			body.Blocks = append(body.Blocks, Block{
+				Type:    BlockParagraph,
+				Inlines: []Inline{{Type: InlineText, Text: paragraph}},
+			})
+		}
+	}
+
+	return body
+}
+
+// IsEmpty reports whether the body has no blocks.
+func (b Body) IsEmpty() bool {
+	return len(b.Blocks) == 0
+}
+
+// Render returns the body in the given format.
+func (b Body) Render(format BodyFormat) string {
+	switch format {
+	case FormatHTML:
+		return b.HTML()
+	case FormatMarkdown:
+		return b.Markdown()
+	default:
+		return b.Text()
+	}
+}
+
+// HTML renders the body with a fixed set of tags. Text and attributes
+// are escaped and links or sources with schemes other than http, https
+// (and mailto for links) are dropped, so the output is safe to embed.
+func (b Body) HTML() string {
+	blocks := make([]string, 0, len(b.Blocks))
+	for _, block := range b.Blocks {
+		switch block.Type {
+		case BlockHeading:
+			tag := "h" + strconv.Itoa(headingLevel(block.Level))
+			blocks = append(blocks, "<"+tag+">"+inlinesHTML(block.Inlines)+"</"+tag+">")
+		case BlockQuote:
+			blocks = append(blocks, "<blockquote><p>"+inlinesHTML(block.Inlines)+"</p></blockquote>")
+		case BlockImage:
+			if IsSafeURL(block.Src, false) {
+				blocks = append(blocks, `<img src="`+html.EscapeString(block.Src)+`" alt="`+html.EscapeString(block.Alt)+`">`)
+			}
+		case BlockEmbed:
+			if IsSafeURL(block.Src, false) {
+				blocks = append(blocks, `<iframe src="`+html.EscapeString(block.Src)+`" allowfullscreen></iframe>`)
+			}
+		default:
+			blocks = append(blocks, "<p>"+inlinesHTML(block.Inlines)+"</p>")
+		}
+	}
+
+	return strings.Join(blocks, "\n")
+}
+
+// Markdown renders the body as CommonMark.
+func (b Body) Markdown() string {
+	blocks := make([]string, 0, len(b.Blocks))
+	for _, block := range b.Blocks {
+		switch block.Type {
+		case BlockHeading:
+			blocks = append(blocks, strings.Repeat("#", headingLevel(block.Level))+" "+inlinesMarkdown(block.Inlines))
+		case BlockQuote:
+			blocks = append(blocks, "> "+strings.ReplaceAll(inlinesMarkdown(block.Inlines), "\n", "\n> "))
+		case BlockImage:
+			if IsSafeURL(block.Src, false) {
+				blocks = append(blocks, "!["+escapeMarkdown(block.Alt)+"]("+markdownURL(block.Src)+")")
+			}
+		case BlockEmbed:
+			if IsSafeURL(block.Src, false) {
+				blocks = append(blocks, "<"+markdownURL(block.Src)+">")
+			}
+		default:
+			blocks = append(blocks, escapeLineStart(inlinesMarkdown(block.Inlines)))
+		}
+	}
+
+	return strings.Join(blocks, "\n\n")
+}
+
+// Text renders the body as plain text, images are replaced by their
+// alternative text and embeds are left out.
+func (b Body) Text() string {
+	blocks := make([]string, 0, len(b.Blocks))
+	for _, block := range b.Blocks {
+		var text string
+		switch block.Type {
+		case BlockImage:
+			text = block.Alt
+		case BlockEmbed:
+		default:
+			text = inlinesText(block.Inlines)
+		}
+
+		if text != "" {
+			blocks = append(blocks, text)
+		}
+	}
+
+	return strings.Join(blocks, "\n\n")
+}
+
+// IsSafeURL reports whether value is a relative URL or an absolute one
+// with an http or https scheme, or mailto when links are allowed.
+// Protocol-relative URLs point to another host and are rejected.
+func IsSafeURL(value string, link bool) bool {
+	if value == "" || strings.HasPrefix(value, "\\") || strings.HasPrefix(value, "/\\") {
+		return false
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme == "" && u.Host != "") {
+		return false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https":
+		return true
+	case "mailto":
+		return link
+	default:
+		return false
+	}
+}
+
+func headingLevel(level int) int {
+	if level < 1 || level > 6 {
+		return 2
+	}
+
+	return level
+}
+
+func inlinesHTML(inlines []Inline) string {
+	var sb strings.Builder
+	for _, in := range inlines {
+		switch in.Type {
+		case InlineLink:
+			if !IsSafeURL(in.Href, true) {
+				sb.WriteString(inlinesHTML(in.Children))
+				continue
+			}
+
+			sb.WriteString(`<a href="` + html.EscapeString(in.Href) + `" rel="nofollow noopener noreferrer">`)
+			sb.WriteString(inlinesHTML(in.Children))
+			sb.WriteString("</a>")
+		case InlineEmphasis:
+			sb.WriteString("<em>" + inlinesHTML(in.Children) + "</em>")
+		case InlineStrong:
+			sb.WriteString("<strong>" + inlinesHTML(in.Children) + "</strong>")
+		case InlineLineBreak:
+			sb.WriteString("<br>")
+		default:
+			sb.WriteString(html.EscapeString(in.Text))
+		}
+	}
+
+	return sb.String()
+}
+
+func inlinesMarkdown(inlines []Inline) string {
+	var sb strings.Builder
+	for _, in := range inlines {
+		switch in.Type {
+		case InlineLink:
+			if !IsSafeURL(in.Href, true) {
+				sb.WriteString(inlinesMarkdown(in.Children))
+				continue
+			}
+
+			sb.WriteString("[" + inlinesMarkdown(in.Children) + "](" + markdownURL(in.Href) + ")")
+		case InlineEmphasis:
+			sb.WriteString("*" + inlinesMarkdown(in.Children) + "*")
+		case InlineStrong:
+			sb.WriteString("**" + inlinesMarkdown(in.Children) + "**")
+		case InlineLineBreak:
+			sb.WriteString("\\\n")
+		default:
+			sb.WriteString(escapeMarkdown(in.Text))
+		}
+	}
+
+	return sb.String()
+}
+
+func inlinesText(inlines []Inline) string {
+	var sb strings.Builder
+	for _, in := range inlines {
+		switch in.Type {
+		case InlineLineBreak:
+			sb.WriteString("\n")
+		case InlineText:
+			sb.WriteString(in.Text)
+		default:
+			sb.WriteString(inlinesText(in.Children))
+		}
+	}
+
+	return sb.String()
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`,
+	"[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`,
+)
+
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// escapeLineStart keeps a paragraph from being read as a heading or a list.
+func escapeLineStart(text string) string {
+	if text != "" && strings.ContainsRune("#-+", rune(text[0])) {
+		return `\` + text
+	}
+
+	return text
+}
+
+// markdownURL percent-encodes the characters that would end a link
+// destination or an autolink early.
+func markdownURL(value string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E").Replace(value)
+}
@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockBody() Body {
+	return Body{Blocks: []Block{
+		{Type: BlockHeading, Level: 2, Inlines: []Inline{{Type: InlineText, Text: "Match report"}}},
+		{Type: BlockParagraph, Inlines: []Inline{
+			{Type: InlineText, Text: "Read <more> on "},
+			{Type: InlineLink, Href: "https://www.dbu.dk/?a=1&b=2", Children: []Inline{
+				{Type: InlineEmphasis, Children: []Inline{{Type: InlineText, Text: "the_DBU"}}},
+			}},
+			{Type: InlineLineBreak},
+			{Type: InlineStrong, Children: []Inline{{Type: InlineText, Text: "Full time"}}},
+		}},
+		{Type: BlockQuote, Inlines: []Inline{{Type: InlineText, Text: "We were brilliant"}}},
+		{Type: BlockImage, Src: "https://example.com/a b.jpg", Alt: "Thomas \"Frank\""},
+		{Type: BlockEmbed, Src: "https://www.youtube.com/embed/xyz"},
+		{Type: BlockParagraph, Inlines: []Inline{
+			{Type: InlineText, Text: "# not a heading "},
+			{Type: InlineLink, Href: "javascript:alert(1)", Children: []Inline{{Type: InlineText, Text: "click"}}},
+		}},
+	}}
+}
+
+func TestBody_HTML(t *testing.T) {
+	want := `<h2>Match report</h2>
+<p>Read &lt;more&gt; on <a href="https://www.dbu.dk/?a=1&amp;b=2" rel="nofollow noopener noreferrer"><em>the_DBU</em></a><br><strong>Full time</strong></p>
+<blockquote><p>We were brilliant</p></blockquote>
+<img src="https://example.com/a b.jpg" alt="Thomas &#34;Frank&#34;">
+<iframe src="https://www.youtube.com/embed/xyz" allowfullscreen></iframe>
+<p># not a heading click</p>`
+	assert.Equal(t, want, mockBody().HTML())
+}
+
+func TestBody_Markdown(t *testing.T) {
+	want := `## Match report
+
+Read \<more\> on [*the\_DBU*](https://www.dbu.dk/?a=1&b=2)\
+**Full time**
+
+> We were brilliant
+
+![Thomas "Frank"](https://example.com/a%20b.jpg)
+
+<https://www.youtube.com/embed/xyz>
+
+\# not a heading click`
+	assert.Equal(t, want, mockBody().Markdown())
+}
+
+func TestBody_Markdown_embedURL(t *testing.T) {
+	body := Body{Blocks: []Block{
+		{Type: BlockEmbed, Src: "https://www.youtube.com/embed/x y><img src=x onerror=alert(1)>"},
+	}}
+
+	want := "<https://www.youtube.com/embed/x%20y%3E%3Cimg%20src=x%20onerror=alert%281%29%3E>"
+	assert.Equal(t, want, body.Markdown())
+}
+
+func TestBody_Text(t *testing.T) {
+	want := "Match report\n\nRead <more> on the_DBU\nFull time\n\nWe were brilliant\n\nThomas \"Frank\"\n\n# not a heading click"
+	assert.Equal(t, want, mockBody().Text())
+}
+
+func TestParseBodyFormat(t *testing.T) {
+	format, err := ParseBodyFormat("Markdown")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatMarkdown, format)
+
+	_, err = ParseBodyFormat("pdf")
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+}
+
+func TestArticleNews_Hash_body(t *testing.T) {
+	article := ArticleNews{NewsID: "1", Body: mockBody()}
+	changed := article
+	changed.Body = Body{Blocks: append([]Block{}, article.Body.Blocks...)}
+	changed.Body.Blocks[1] = Block{Type: BlockParagraph, Inlines: []Inline{{Type: InlineText, Text: "Read more"}}}
+
+	assert.NotEqual(t, article.Hash(), changed.Hash())
+	assert.True(t, changed.ChangedFrom(article))
+}
+
+func TestArticleNews_ChangedFrom_fillsBody(t *testing.T) {
+	body := mockBody()
+	stored := ArticleNews{NewsID: "1", BodyText: body.Text(), LastUpdateDate: "2022-01-01 10:00:00"}
+	article := stored
+	article.Body = body
+
+	assert.True(t, article.FillsBody(stored))
+	assert.False(t, article.ChangedFrom(stored))
+	assert.False(t, article.FillsBody(article))
+
+	article.Title = "Match report"
+	assert.True(t, article.ChangedFrom(stored))
+}
+
+func TestIsSafeURL(t *testing.T) {
+	tests := []struct {
+		value string
+		link  bool
+		want  bool
+	}{
+		{value: "https://example.com/a.jpg", want: true},
+		{value: "/news/a.jpg", want: true},
+		{value: "a.jpg", want: true},
+		{value: "mailto:press@example.com", link: true, want: true},
+		{value: "mailto:press@example.com"},
+		{value: "javascript:alert(1)", link: true},
+		{value: "//evil.example.com/a.jpg", link: true},
+		{value: "\\\\evil.example.com/a.jpg", link: true},
+		{value: "/\\evil.example.com/a.jpg", link: true},
+		{value: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsSafeURL(tt.value, tt.link))
+		})
+	}
+}
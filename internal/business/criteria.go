@@ -0,0 +1,137 @@
+package business
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+)
+
+const dateLayout = "2006-01-02"
+
+// Criteria holds the parameters received to list articles.
+type Criteria struct {
+	Limit  int
+	Cursor string
+	Sort   string
+
+	Club      string
+	Taxonomy  string
+	Published string
+	// From and To accept a date (2006-01-02) or a RFC 3339 timestamp.
+	// Both are inclusive, a date in To covers the whole day.
+	From string
+	To   string
+	// IncludeHidden lists deleted and unpublished articles too, they
+	// are left out by default.
+	IncludeHidden bool
+	// IncludeArchived lists the articles archived by the retention
+	// policy too.
+	IncludeArchived bool
+}
+
+func (c Criteria) toQuery() (internal.ArticleQuery, error) {
+	if c.Limit < 0 || c.Limit > internal.MaxPageLimit {
+		return internal.ArticleQuery{}, internal.ErrInvalidLimit
+	}
+
+	limit := c.Limit
+	if limit == 0 {
+		limit = internal.DefaultPageLimit
+	}
+
+	sort, err := internal.ParseSort(c.Sort)
+	if err != nil {
+		return internal.ArticleQuery{}, err
+	}
+
+	if c.Cursor != "" {
+		if _, err = internal.DecodeCursor(c.Cursor, sort); err != nil {
+			return internal.ArticleQuery{}, err
+		}
+	}
+
+	filter, err := c.toFilter()
+	if err != nil {
+		return internal.ArticleQuery{}, err
+	}
+
+	return internal.ArticleQuery{
+		Filter: filter,
+		Limit:  limit,
+		Cursor: c.Cursor,
+		Sort:   sort,
+
+		IncludeArchived: c.IncludeArchived,
+	}, nil
+}
+
+func (c Criteria) toFilter() (internal.ArticleFilter, error) {
+	filter := internal.ArticleFilter{
+		ClubName: c.Club,
+		Taxonomy: c.Taxonomy,
+	}
+
+	if c.Published != "" {
+		published, err := strconv.ParseBool(c.Published)
+		if err != nil {
+			return internal.ArticleFilter{}, internal.ErrInvalidFilter
+		}
+
+		filter.Published = &published
+	}
+
+	if !c.IncludeHidden {
+		if filter.Published != nil && !*filter.Published {
+			return internal.ArticleFilter{}, internal.ErrHiddenFilter
+		}
+
+		published, deleted := true, false
+		filter.Published = &published
+		filter.Deleted = &deleted
+	}
+
+	if c.From != "" {
+		from, _, err := parseDate(c.From)
+		if err != nil {
+			return internal.ArticleFilter{}, err
+		}
+
+		filter.From = from
+	}
+
+	if c.To != "" {
+		to, isDate, err := parseDate(c.To)
+		if err != nil {
+			return internal.ArticleFilter{}, err
+		}
+
+		if isDate {
+			to = to.AddDate(0, 0, 1)
+		} else {
+			to = to.Add(time.Second)
+		}
+
+		filter.To = to
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return internal.ArticleFilter{}, internal.ErrInvalidFilter
+	}
+
+	return filter, nil
+}
+
+// parseDate reports whether value was a date without time.
+func parseDate(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, true, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, internal.ErrInvalidFilter
+	}
+
+	return t.UTC(), false, nil
+}
@@ -0,0 +1,90 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+)
+
+func TestCriteria_toFilter(t *testing.T) {
+	published, unpublished, deleted := true, false, false
+	tests := []struct {
+		name     string
+		criteria Criteria
+		want     internal.ArticleFilter
+		wantErr  error
+	}{
+		{
+			name: "empty criteria hides deleted and unpublished articles",
+			want: internal.ArticleFilter{Published: &published, Deleted: &deleted},
+		},
+		{
+			name:     "include hidden",
+			criteria: Criteria{IncludeHidden: true},
+			want:     internal.ArticleFilter{},
+		},
+		{
+			name:     "unpublished with include hidden",
+			criteria: Criteria{Published: "false", IncludeHidden: true},
+			want:     internal.ArticleFilter{Published: &unpublished},
+		},
+		{
+			name:     "unpublished without include hidden",
+			criteria: Criteria{Published: "false"},
+			wantErr:  internal.ErrHiddenFilter,
+		},
+		{
+			name: "all the filters",
+			criteria: Criteria{
+				Club:      "Brentford",
+				Taxonomy:  "History",
+				Published: "true",
+				From:      "2022-06-01",
+				To:        "2022-06-30",
+			},
+			want: internal.ArticleFilter{
+				ClubName:  "Brentford",
+				Taxonomy:  "History",
+				Published: &published,
+				Deleted:   &deleted,
+				From:      time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+				To:        time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "timestamps",
+			criteria: Criteria{From: "2022-06-01T10:00:00Z", To: "2022-06-01T12:00:00+01:00"},
+			want: internal.ArticleFilter{
+				Published: &published,
+				Deleted:   &deleted,
+				From:      time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC),
+				To:        time.Date(2022, 6, 1, 11, 0, 1, 0, time.UTC),
+			},
+		},
+		{
+			name:     "invalid published",
+			criteria: Criteria{Published: "yes please"},
+			wantErr:  internal.ErrInvalidFilter,
+		},
+		{
+			name:     "invalid date",
+			criteria: Criteria{From: "01/06/2022"},
+			wantErr:  internal.ErrInvalidFilter,
+		},
+		{
+			name:     "from after to",
+			criteria: Criteria{From: "2022-06-30", To: "2022-06-01"},
+			wantErr:  internal.ErrInvalidFilter,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.criteria.toFilter()
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
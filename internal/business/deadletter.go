@@ -0,0 +1,70 @@
+package business
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+)
+
+// Retrier processes the item of a dead letter again, the news pipeline
+// implements it.
+type Retrier interface {
+	Retry(ctx context.Context, deadLetter internal.DeadLetter) error
+}
+
+// DeadLetterService lists the items the pipeline could not store, and
+// retries or drops them.
+type DeadLetterService interface {
+	GetDeadLetters(ctx context.Context) ([]internal.DeadLetter, error)
+	RetryDeadLetter(ctx context.Context, provider, articleID string) error
+	DropDeadLetter(ctx context.Context, provider, articleID string) error
+}
+
+type deadLetterService struct {
+	repository internal.Storage
+	retrier    Retrier
+	log        logger.Logger
+}
+
+// NewDeadLetterService returns the default DeadLetterService interface
+// implementation.
+func NewDeadLetterService(repository internal.Storage, retrier Retrier, log logger.Logger) DeadLetterService {
+	return &deadLetterService{
+		repository: repository,
+		retrier:    retrier,
+		log:        log,
+	}
+}
+
+func (s deadLetterService) GetDeadLetters(ctx context.Context) ([]internal.DeadLetter, error) {
+	deadLetters, err := s.repository.GetDeadLetters(ctx)
+	if err != nil {
+		s.log.Errorf("error GetDeadLetters:%s", err.Error())
+		return nil, err
+	}
+
+	return deadLetters, nil
+}
+
+// RetryDeadLetter processes the item of the dead letter again. The dead
+// letter is deleted when the article is stored, and ErrRetryFailed is
+// returned with the error of the item when it fails again.
+func (s deadLetterService) RetryDeadLetter(ctx context.Context, provider, articleID string) error {
+	deadLetter, err := s.repository.GetDeadLetter(ctx, provider, articleID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.retrier.Retry(ctx, *deadLetter); err != nil {
+		s.log.Errorf("error RetryDeadLetter %s ID:%s:%s", provider, articleID, err.Error())
+		return fmt.Errorf("%w: %s", internal.ErrRetryFailed, err.Error())
+	}
+
+	return nil
+}
+
+func (s deadLetterService) DropDeadLetter(ctx context.Context, provider, articleID string) error {
+	return s.repository.DeleteDeadLetter(ctx, provider, articleID)
+}
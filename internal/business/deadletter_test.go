@@ -0,0 +1,77 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/memory"
+)
+
+type mockRetrier struct {
+	err     error
+	retried []internal.DeadLetter
+}
+
+func (m *mockRetrier) Retry(_ context.Context, deadLetter internal.DeadLetter) error {
+	m.retried = append(m.retried, deadLetter)
+	return m.err
+}
+
+func Test_deadLetterService_RetryDeadLetter(t *testing.T) {
+	deadLetter := internal.DeadLetter{
+		ArticleID:  "641838",
+		Provider:   "brentford",
+		ArticleURL: "https://www.brentfordfc.com/api/incrowd/getnewsarticleinformation?id=%s",
+		Stage:      "fetch",
+		Error:      "unknown error",
+		Attempts:   3,
+	}
+	tests := []struct {
+		name        string
+		provider    string
+		articleID   string
+		retryErr    error
+		wantErr     error
+		wantRetried int
+	}{
+		{name: "retried", provider: "brentford", articleID: "641838", wantRetried: 1},
+		{name: "failed again", provider: "brentford", articleID: "641838", retryErr: errors.New("unknown error"), wantErr: internal.ErrRetryFailed, wantRetried: 1},
+		{name: "unknown dead letter", provider: "brentford", articleID: "1", wantErr: internal.ErrDeadLetterNotFound},
+		{name: "article of another provider", provider: "arsenal", articleID: "641838", wantErr: internal.ErrDeadLetterNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := memory.NewStorage()
+			require.NoError(t, repository.SaveDeadLetter(context.Background(), deadLetter))
+			retrier := &mockRetrier{err: tt.retryErr}
+			s := NewDeadLetterService(repository, retrier, logger.New())
+
+			err := s.RetryDeadLetter(context.Background(), tt.provider, tt.articleID)
+			assert.ErrorIs(t, err, tt.wantErr)
+			require.Len(t, retrier.retried, tt.wantRetried)
+			if tt.wantRetried > 0 {
+				assert.Equal(t, deadLetter, retrier.retried[0])
+			}
+		})
+	}
+}
+
+func Test_deadLetterService_DropDeadLetter(t *testing.T) {
+	repository := memory.NewStorage()
+	require.NoError(t, repository.SaveDeadLetter(context.Background(), internal.DeadLetter{ArticleID: "641838", Provider: "brentford"}))
+	s := NewDeadLetterService(repository, &mockRetrier{}, logger.New())
+
+	assert.ErrorIs(t, s.DropDeadLetter(context.Background(), "arsenal", "641838"), internal.ErrDeadLetterNotFound)
+	require.NoError(t, s.DropDeadLetter(context.Background(), "brentford", "641838"))
+	assert.ErrorIs(t, s.DropDeadLetter(context.Background(), "brentford", "641838"), internal.ErrDeadLetterNotFound)
+
+	deadLetters, err := s.GetDeadLetters(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, deadLetters)
+}
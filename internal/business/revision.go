@@ -0,0 +1,81 @@
+package business
+
+import (
+	"context"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+)
+
+// RevisionDiff is a version of an article compared with another one.
+// The current version of the article is numbered after its last revision.
+type RevisionDiff struct {
+	Revision   internal.Revision
+	ComparedTo int
+	Changes    []internal.FieldChange
+}
+
+// GetRevisions returns the previous versions of the article, the ones of
+// hidden articles only when includeHidden is set.
+func (s service) GetRevisions(ctx context.Context, articleID string, includeHidden bool) ([]internal.Revision, error) {
+	if _, err := s.GetArticleByID(ctx, articleID, includeHidden); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.repository.GetRevisions(ctx, articleID)
+	if err != nil {
+		s.log.Errorf("error GetRevisions ID:%s:%s", articleID, err.Error())
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetRevision returns the version of the article compared with compareTo,
+// or with the version that replaced it when compareTo is 0. Changes always
+// go from the older version to the newer one.
+func (s service) GetRevision(ctx context.Context, articleID string, number, compareTo int, includeHidden bool) (*RevisionDiff, error) {
+	current, err := s.GetArticleByID(ctx, articleID, includeHidden)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.repository.GetRevisions(ctx, articleID)
+	if err != nil {
+		s.log.Errorf("error GetRevisions ID:%s:%s", articleID, err.Error())
+		return nil, err
+	}
+
+	versions := append(revisions, internal.Revision{Number: len(revisions) + 1, Article: *current})
+	if number < 1 || number > len(versions) {
+		return nil, internal.ErrRevisionNotFound
+	}
+
+	if compareTo == 0 {
+		compareTo = number + 1
+		if number == len(versions) {
+			compareTo = number - 1
+		}
+	}
+
+	diff := &RevisionDiff{
+		Revision: versions[number-1],
+		Changes:  make([]internal.FieldChange, 0),
+	}
+	if compareTo < 1 {
+		return diff, nil
+	}
+
+	if compareTo > len(versions) {
+		return nil, internal.ErrRevisionNotFound
+	}
+
+	older, newer := versions[number-1], versions[compareTo-1]
+	if compareTo < number {
+		older, newer = newer, older
+	}
+
+	diff.ComparedTo = compareTo
+	diff.Changes = internal.Diff(older.Article, newer.Article)
+
+	return diff, nil
+}
@@ -0,0 +1,64 @@
+package business
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/storagemocks"
+)
+
+func Test_service_GetRevision(t *testing.T) {
+	first := mockArticle()
+	second := first
+	second.Title = "Pontus explains again"
+	current := second
+	current.Subtitle = "Fatherhood"
+
+	repoMock := new(storagemocks.Storage)
+	repoMock.On("GetArticleByID", mock.Anything, first.NewsID).Return(&current, nil)
+	repoMock.On("GetRevisions", mock.Anything, first.NewsID).Return([]internal.Revision{
+		{Number: 1, Article: first},
+		{Number: 2, Article: second},
+	}, nil)
+	s := NewService(repoMock, logger.New())
+
+	tests := []struct {
+		name       string
+		number     int
+		compareTo  int
+		comparedTo int
+		changes    []string
+		wantErr    error
+	}{
+		{name: "with the next version", number: 1, comparedTo: 2, changes: []string{"title"}},
+		{name: "current with the previous version", number: 3, comparedTo: 2, changes: []string{"subtitle"}},
+		{name: "with another version", number: 3, compareTo: 1, comparedTo: 1, changes: []string{"title", "subtitle"}},
+		{name: "unknown revision", number: 4, wantErr: internal.ErrRevisionNotFound},
+		{name: "unknown version to compare", number: 1, compareTo: 4, wantErr: internal.ErrRevisionNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.GetRevision(context.Background(), first.NewsID, tt.number, tt.compareTo, false)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr != nil {
+				return
+			}
+
+			require.NotNil(t, got)
+			assert.Equal(t, tt.number, got.Revision.Number)
+			assert.Equal(t, tt.comparedTo, got.ComparedTo)
+
+			var fields []string
+			for _, c := range got.Changes {
+				fields = append(fields, c.Field)
+			}
+			assert.Equal(t, tt.changes, fields)
+		})
+	}
+}
@@ -2,14 +2,18 @@ package business
 
 import (
 	"context"
+	"strings"
 
 	"github.com/patriciabonaldy/sports-news/internal"
 	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
 )
 
 type Service interface {
-	GetArticles(ctx context.Context) ([]internal.ArticleNews, error)
-	GetArticleByID(ctx context.Context, articleID string) (*internal.ArticleNews, error)
+	GetArticles(ctx context.Context, criteria Criteria) (*internal.ArticlePage, error)
+	GetArticleByID(ctx context.Context, articleID string, includeHidden bool) (*internal.ArticleNews, error)
+	Search(ctx context.Context, text string, limit int) ([]internal.SearchResult, error)
+	GetRevisions(ctx context.Context, articleID string, includeHidden bool) ([]internal.Revision, error)
+	GetRevision(ctx context.Context, articleID string, number, compareTo int, includeHidden bool) (*RevisionDiff, error)
 }
 
 type service struct {
@@ -25,22 +29,55 @@ func NewService(repository internal.Storage, log logger.Logger) Service {
 	}
 }
 
-func (s service) GetArticleByID(ctx context.Context, articleID string) (*internal.ArticleNews, error) {
+// GetArticleByID returns the article, deleted and unpublished articles
+// are not found unless includeHidden is set.
+func (s service) GetArticleByID(ctx context.Context, articleID string, includeHidden bool) (*internal.ArticleNews, error) {
 	article, err := s.repository.GetArticleByID(ctx, articleID)
 	if err != nil {
 		s.log.Errorf("error GetArticleByID ID:%s:%s", articleID, err.Error())
 		return nil, err
 	}
 
+	if article.IsHidden() && !includeHidden {
+		return nil, internal.ErrArticleNotFound
+	}
+
 	return article, nil
 }
 
-func (s service) GetArticles(ctx context.Context) ([]internal.ArticleNews, error) {
-	articles, err := s.repository.GetArticles(ctx)
+func (s service) GetArticles(ctx context.Context, criteria Criteria) (*internal.ArticlePage, error) {
+	query, err := criteria.toQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.repository.GetArticlesPage(ctx, query)
 	if err != nil {
 		s.log.Errorf("error Get Articles:%s", err.Error())
 		return nil, err
 	}
 
-	return articles, nil
+	return page, nil
+}
+
+func (s service) Search(ctx context.Context, text string, limit int) ([]internal.SearchResult, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, internal.ErrInvalidSearch
+	}
+
+	if limit < 0 || limit > internal.MaxPageLimit {
+		return nil, internal.ErrInvalidLimit
+	}
+
+	if limit == 0 {
+		limit = internal.DefaultPageLimit
+	}
+
+	results, err := s.repository.Search(ctx, internal.SearchQuery{Text: text, Limit: limit})
+	if err != nil {
+		s.log.Errorf("error Search %q:%s", text, err.Error())
+		return nil, err
+	}
+
+	return results, nil
 }
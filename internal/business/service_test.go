@@ -16,10 +16,11 @@ import (
 
 func Test_service_GetArticleByID(t *testing.T) {
 	tests := []struct {
-		name    string
-		repo    func() internal.Storage
-		want    func() *internal.ArticleNews
-		wantErr bool
+		name          string
+		repo          func() internal.Storage
+		includeHidden bool
+		want          func() *internal.ArticleNews
+		wantErr       bool
 	}{
 		{
 			name: "error getting article",
@@ -50,6 +51,43 @@ func Test_service_GetArticleByID(t *testing.T) {
 			want: func() *internal.ArticleNews {
 				mockA := mockArticle()
 
+				return &mockA
+			},
+		},
+		{
+			name: "unpublished article is not found",
+			repo: func() internal.Storage {
+				mockA := mockArticle()
+				mockA.IsPublished = false
+				repoMock := new(storagemocks.Storage)
+				repoMock.On("GetArticleByID", mock.Anything, mock.Anything).
+					Return(&mockA, nil)
+
+				return repoMock
+
+			},
+			want: func() *internal.ArticleNews {
+				return nil
+			},
+			wantErr: true,
+		},
+		{
+			name: "deleted article including hidden",
+			repo: func() internal.Storage {
+				mockA := mockArticle()
+				mockA.DeletedAt = time.Date(2022, 6, 20, 0, 0, 0, 0, time.UTC)
+				repoMock := new(storagemocks.Storage)
+				repoMock.On("GetArticleByID", mock.Anything, mock.Anything).
+					Return(&mockA, nil)
+
+				return repoMock
+
+			},
+			includeHidden: true,
+			want: func() *internal.ArticleNews {
+				mockA := mockArticle()
+				mockA.DeletedAt = time.Date(2022, 6, 20, 0, 0, 0, 0, time.UTC)
+
 				return &mockA
 			},
 		},
@@ -57,7 +95,7 @@ func Test_service_GetArticleByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := NewService(tt.repo(), logger.New())
-			got, err := s.GetArticleByID(context.Background(), "641838")
+			got, err := s.GetArticleByID(context.Background(), "641838", tt.includeHidden)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetArticleByID() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -73,42 +111,79 @@ func Test_service_GetArticleByID(t *testing.T) {
 
 func Test_service_GetArticles(t *testing.T) {
 	tests := []struct {
-		name    string
-		repo    func() internal.Storage
-		want    []internal.ArticleNews
-		wantErr bool
+		name     string
+		criteria Criteria
+		repo     func() internal.Storage
+		want     *internal.ArticlePage
+		wantErr  error
 	}{
+		{
+			name:     "invalid limit",
+			criteria: Criteria{Limit: internal.MaxPageLimit + 1},
+			repo: func() internal.Storage {
+				return new(storagemocks.Storage)
+			},
+			wantErr: internal.ErrInvalidLimit,
+		},
+		{
+			name:     "invalid sort",
+			criteria: Criteria{Sort: "title"},
+			repo: func() internal.Storage {
+				return new(storagemocks.Storage)
+			},
+			wantErr: internal.ErrInvalidSort,
+		},
+		{
+			name:     "invalid cursor",
+			criteria: Criteria{Cursor: "not-a-cursor"},
+			repo: func() internal.Storage {
+				return new(storagemocks.Storage)
+			},
+			wantErr: internal.ErrInvalidCursor,
+		},
 		{
 			name: "error getting article",
 			repo: func() internal.Storage {
 				repoMock := new(storagemocks.Storage)
-				repoMock.On("GetArticles", mock.Anything).
+				repoMock.On("GetArticlesPage", mock.Anything, mock.Anything).
 					Return(nil, errors.New("something unexpected happened"))
 
 				return repoMock
 
 			},
 			want:    nil,
-			wantErr: true,
+			wantErr: errors.New("something unexpected happened"),
 		},
 		{
 			name: "success",
 			repo: func() internal.Storage {
 				repoMock := new(storagemocks.Storage)
-				repoMock.On("GetArticles", mock.Anything).
-					Return([]internal.ArticleNews{mockArticle()}, nil)
+				published, deleted := true, false
+				repoMock.On("GetArticlesPage", mock.Anything, internal.ArticleQuery{
+					Filter: internal.ArticleFilter{Published: &published, Deleted: &deleted},
+					Limit:  internal.DefaultPageLimit,
+					Sort:   internal.DefaultSort(),
+				}).Return(&internal.ArticlePage{
+					Articles: []internal.ArticleNews{mockArticle()},
+					Limit:    internal.DefaultPageLimit,
+					Sort:     internal.DefaultSort(),
+				}, nil)
 
 				return repoMock
 
 			},
-			want: []internal.ArticleNews{mockArticle()},
+			want: &internal.ArticlePage{
+				Articles: []internal.ArticleNews{mockArticle()},
+				Limit:    internal.DefaultPageLimit,
+				Sort:     internal.DefaultSort(),
+			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := NewService(tt.repo(), logger.New())
-			got, err := s.GetArticles(context.Background())
-			if (err != nil) != tt.wantErr {
+			got, err := s.GetArticles(context.Background(), tt.criteria)
+			if (err != nil) != (tt.wantErr != nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
 				t.Errorf("GetArticles() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
@@ -120,6 +195,73 @@ func Test_service_GetArticles(t *testing.T) {
 	}
 }
 
+func Test_service_Search(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		limit   int
+		repo    func() internal.Storage
+		want    []internal.SearchResult
+		wantErr bool
+	}{
+		{
+			name: "empty text",
+			text: "  ",
+			repo: func() internal.Storage {
+				return new(storagemocks.Storage)
+			},
+			wantErr: true,
+		},
+		{
+			name:  "invalid limit",
+			text:  "Toney",
+			limit: -1,
+			repo: func() internal.Storage {
+				return new(storagemocks.Storage)
+			},
+			wantErr: true,
+		},
+		{
+			name: "error searching",
+			text: "Toney",
+			repo: func() internal.Storage {
+				repoMock := new(storagemocks.Storage)
+				repoMock.On("Search", mock.Anything, mock.Anything).
+					Return(nil, errors.New("something unexpected happened"))
+
+				return repoMock
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			text: "Toney",
+			repo: func() internal.Storage {
+				repoMock := new(storagemocks.Storage)
+				repoMock.On("Search", mock.Anything, internal.SearchQuery{Text: "Toney", Limit: internal.DefaultPageLimit}).
+					Return([]internal.SearchResult{{Article: mockArticle(), Score: 1}}, nil)
+
+				return repoMock
+			},
+			want: []internal.SearchResult{{Article: mockArticle(), Score: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(tt.repo(), logger.New())
+			got, err := s.Search(context.Background(), tt.text, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Search() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func mockArticle() internal.ArticleNews {
 	return internal.ArticleNews{
 		NewsID:         "641838",
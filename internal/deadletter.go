@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"sort"
+	"time"
+)
+
+// DeadLetter is an item of the list of a provider whose detail could not
+// be fetched, parsed or stored after every attempt. It is kept, one per
+// article of a provider, until an admin retries or drops it or a later
+// sync stores the article.
+// ArticleURL is the template of the detail URL of the provider and
+// IsPublished the flag of the item in the list.
+type DeadLetter struct {
+	ArticleID   string
+	Provider    string
+	ArticleURL  string
+	IsPublished bool
+	Stage       string
+	Error       string
+	Attempts    int
+	FailedAt    time.Time
+}
+
+// SortDeadLetters sorts the dead letters oldest first, the order of
+// Storage.GetDeadLetters.
+func SortDeadLetters(deadLetters []DeadLetter) {
+	sort.Slice(deadLetters, func(i, j int) bool {
+		a, b := deadLetters[i], deadLetters[j]
+		if !a.FailedAt.Equal(b.FailedAt) {
+			return a.FailedAt.Before(b.FailedAt)
+		}
+
+		if a.ArticleID != b.ArticleID {
+			return a.ArticleID < b.ArticleID
+		}
+
+		return a.Provider < b.Provider
+	})
+}
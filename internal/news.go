@@ -2,7 +2,11 @@ package internal
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,12 +17,35 @@ var (
 
 	ErrIDIsEmpty       = errors.New("invalid ID")
 	ErrArticleNotFound = errors.New("id not found")
+
+	ErrRevisionNotFound   = errors.New("revision not found")
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	ErrRetryFailed        = errors.New("retry failed")
+
+	ErrInvalidLimit  = errors.New("invalid limit")
+	ErrInvalidSort   = errors.New("invalid sort")
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidFilter = errors.New("invalid filter")
+	ErrHiddenFilter  = errors.New("unpublished articles are only listed with include_hidden")
+	ErrInvalidSearch = errors.New("search text can not be empty")
 )
 
+// Storage persists the articles. The behaviour every implementation
+// must share is the conformance suite of platform/storage/storagetest.
 type Storage interface {
 	GetArticleByID(ctx context.Context, ID string) (*ArticleNews, error)
 	Save(ctx context.Context, news ArticleNews) error
+	Upsert(ctx context.Context, news ArticleNews) (UpsertResult, error)
 	GetArticles(ctx context.Context) ([]ArticleNews, error)
+	GetArticlesPage(ctx context.Context, query ArticleQuery) (*ArticlePage, error)
+	Search(ctx context.Context, query SearchQuery) ([]SearchResult, error)
+	GetRevisions(ctx context.Context, ID string) ([]Revision, error)
+	Withdraw(ctx context.Context, withdrawal Withdrawal) (WithdrawResult, error)
+	Archive(ctx context.Context, query ArchiveQuery) ([]ArticleNews, error)
+	SaveDeadLetter(ctx context.Context, deadLetter DeadLetter) error
+	GetDeadLetters(ctx context.Context) ([]DeadLetter, error)
+	GetDeadLetter(ctx context.Context, provider, articleID string) (*DeadLetter, error)
+	DeleteDeadLetter(ctx context.Context, provider, articleID string) error
 }
 
 //go:generate mockery --case=snake --outpkg=storagemocks --output=platform/storage/storagemocks --name=Storage
@@ -26,20 +53,155 @@ type Storage interface {
 // ArticleNews is a structure o
 type ArticleNews struct {
 	NewsID            string
+	Provider          string
 	ClubName          string
 	ClubWebsiteURL    string
 	ArticleURL        string
 	Title             string
 	Subtitle          string
 	BodyText          string
-	GalleryImageURLs  string
+	Body              Body
+	GalleryImageURLs  []string
 	VideoURL          string
-	Taxonomies        string
+	Taxonomies        []string
 	TeaserText        string
 	ThumbnailImageURL string
-	PublishDate       string
+	PublishDate       time.Time
+	LastUpdateDate    string
 	IsPublished       bool
 	CreateAt          time.Time
+	// DeletedAt is when the provider stopped listing the article, it is
+	// zero for articles that are listed.
+	DeletedAt time.Time
+	// ArchivedAt is when the retention policy moved the article to the
+	// archive, it is zero for articles that are not archived.
+	ArchivedAt time.Time
+}
+
+// UpsertResult is the outcome of Storage.Upsert.
+type UpsertResult int
+
+const (
+	UpsertUnchanged UpsertResult = iota
+	UpsertInserted
+	UpsertUpdated
+)
+
+func (u UpsertResult) String() string {
+	switch u {
+	case UpsertInserted:
+		return "inserted"
+	case UpsertUpdated:
+		return "updated"
+	default:
+		return "unchanged"
+	}
+}
+
+// Hash returns a digest of the content of the article. NewsID, Provider,
+// LastUpdateDate, CreateAt, DeletedAt and ArchivedAt are not part of the
+// content.
+func (a ArticleNews) Hash() string {
+	return a.hash(true)
+}
+
+func (a ArticleNews) hash(withBody bool) string {
+	h := sha256.New()
+	for _, field := range a.contentFields() {
+		if field.name == "body_text" && !withBody {
+			continue
+		}
+
+		h.Write([]byte(field.value))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type contentField struct {
+	name  string
+	value string
+}
+
+func (a ArticleNews) contentFields() []contentField {
+	return []contentField{
+		{name: "club_name", value: a.ClubName},
+		{name: "club_website_url", value: a.ClubWebsiteURL},
+		{name: "article_url", value: a.ArticleURL},
+		{name: "title", value: a.Title},
+		{name: "subtitle", value: a.Subtitle},
+		{name: "body_text", value: a.bodyContent()},
+		{name: "gallery_image_urls", value: strings.Join(a.GalleryImageURLs, ",")},
+		{name: "video_url", value: a.VideoURL},
+		{name: "taxonomies", value: strings.Join(a.Taxonomies, ",")},
+		{name: "teaser_text", value: a.TeaserText},
+		{name: "thumbnail_image_url", value: a.ThumbnailImageURL},
+		{name: "publish_date", value: FormatPublishDate(a.PublishDate)},
+		{name: "is_published", value: strconv.FormatBool(a.IsPublished)},
+	}
+}
+
+// bodyContent is the plain text of the body, as BodyText was hashed
+// before articles had a Body.
+func (a ArticleNews) bodyContent() string {
+	if a.Body.IsEmpty() {
+		return a.BodyText
+	}
+
+	return a.Body.Text()
+}
+
+// RichBody returns the Body of the article, or its BodyText as
+// paragraphs for articles stored without Body.
+func (a ArticleNews) RichBody() Body {
+	if a.Body.IsEmpty() {
+		return TextBody(a.BodyText)
+	}
+
+	return a.Body
+}
+
+// HasTaxonomy reports whether the article is tagged with taxonomy.
+func (a ArticleNews) HasTaxonomy(taxonomy string) bool {
+	for _, t := range a.Taxonomies {
+		if t == taxonomy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsDeleted reports whether the provider stopped listing the article.
+func (a ArticleNews) IsDeleted() bool {
+	return !a.DeletedAt.IsZero()
+}
+
+// IsArchived reports whether the article was moved to the archive.
+func (a ArticleNews) IsArchived() bool {
+	return !a.ArchivedAt.IsZero()
+}
+
+// IsHidden reports whether the article is deleted or unpublished.
+func (a ArticleNews) IsHidden() bool {
+	return a.IsDeleted() || !a.IsPublished
+}
+
+// ChangedFrom reports whether the article is a new version of previous,
+// a deleted article that is listed again is restored. The body of an
+// article stored before it was parsed is not compared, see FillsBody.
+func (a ArticleNews) ChangedFrom(previous ArticleNews) bool {
+	withBody := !a.FillsBody(previous)
+	return a.LastUpdateDate != previous.LastUpdateDate || a.hash(withBody) != previous.hash(withBody) ||
+		a.IsDeleted() != previous.IsDeleted()
+}
+
+// FillsBody reports whether the article has the Body that previous, stored
+// before bodies were parsed, lacks. Storages replace previous with it
+// without a revision when it did not change otherwise.
+func (a ArticleNews) FillsBody(previous ArticleNews) bool {
+	return previous.Body.IsEmpty() && !a.Body.IsEmpty()
 }
 
 func NewArticle() ArticleNews {
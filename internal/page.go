@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Matches reports whether the article passes the filter.
+func (f ArticleFilter) Matches(article ArticleNews) bool {
+	if f.ClubName != "" && article.ClubName != f.ClubName {
+		return false
+	}
+
+	if f.Taxonomy != "" && !article.HasTaxonomy(f.Taxonomy) {
+		return false
+	}
+
+	if f.Published != nil && article.IsPublished != *f.Published {
+		return false
+	}
+
+	if f.Deleted != nil && article.IsDeleted() != *f.Deleted {
+		return false
+	}
+
+	if !f.From.IsZero() && article.PublishDate.Before(f.From) {
+		return false
+	}
+
+	if !f.To.IsZero() && !article.PublishDate.Before(f.To) {
+		return false
+	}
+
+	return true
+}
+
+// Paginate returns the page of the query out of articles, for the
+// storages that read every article, with the same ordering and cursors
+// as the mongo storage.
+func Paginate(articles []ArticleNews, query ArticleQuery) (*ArticlePage, error) {
+	var after *Cursor
+	if query.Cursor != "" {
+		cursor, err := DecodeCursor(query.Cursor, query.Sort)
+		if err != nil {
+			return nil, err
+		}
+
+		after = &cursor
+	}
+
+	var results []ArticleNews
+	for _, article := range articles {
+		if query.Filter.Matches(article) {
+			results = append(results, article)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return query.Sort.less(query.Sort.key(results[i]), results[i].NewsID,
+			query.Sort.key(results[j]), results[j].NewsID)
+	})
+
+	if after != nil {
+		from := sort.Search(len(results), func(i int) bool {
+			return query.Sort.less(after.Key, after.ID, query.Sort.key(results[i]), results[i].NewsID)
+		})
+		results = results[from:]
+	}
+
+	page := &ArticlePage{
+		Articles: make([]ArticleNews, 0, query.Limit),
+		Limit:    query.Limit,
+		Sort:     query.Sort,
+	}
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+		last := results[len(results)-1]
+		page.Next = Cursor{
+			Sort: query.Sort.String(),
+			Key:  query.Sort.key(last),
+			ID:   last.NewsID,
+		}.Encode()
+	}
+
+	page.Articles = append(page.Articles, results...)
+
+	return page, nil
+}
+
+// key returns the sort key of the article as stored in the cursors,
+// create_at is kept with a precision of seconds like in mongo.
+func (s Sort) key(article ArticleNews) string {
+	if s.Field == SortByCreateAt {
+		return strconv.FormatInt(article.CreateAt.Unix(), 10)
+	}
+
+	return FormatPublishDate(article.PublishDate)
+}
+
+func (s Sort) less(keyA, idA, keyB, idB string) bool {
+	if s.Field == SortByCreateAt {
+		a, _ := strconv.ParseInt(keyA, 10, 64)
+		b, _ := strconv.ParseInt(keyB, 10, 64)
+		if a != b {
+			return (a < b) == s.Ascending
+		}
+	} else if keyA != keyB {
+		return (keyA < keyB) == s.Ascending
+	}
+
+	return idA != idB && (idA < idB) == s.Ascending
+}
@@ -0,0 +1,164 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/patriciabonaldy/big_queue/pkg"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+)
+
+// RetryPolicy is how the subscriber handles a message whose callback
+// fails: it is tried up to Attempts times, waiting Backoff doubled after
+// every attempt up to MaxBackoff, and then published to the dead-letter
+// topic.
+type RetryPolicy struct {
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// backoff returns the wait after the given attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.Backoff
+	for i := 1; i < attempt && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+
+	return wait
+}
+
+// DeadLetter is a message the subscriber could not process, as it is
+// published to the dead-letter topic. Permanent reports whether the
+// callback rejected the message at once, without retries.
+type DeadLetter struct {
+	Message   Message   `json:"message"`
+	Error     string    `json:"error"`
+	Attempts  int       `json:"attempts"`
+	Permanent bool      `json:"permanent"`
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+// newDeadLetterMessage wraps the dead letter in a message, the format of
+// every topic.
+func newDeadLetterMessage(deadLetter DeadLetter) (Message, error) {
+	data, err := json.Marshal(deadLetter)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		EventID:   deadLetter.Message.EventID,
+		RawData:   data,
+		Timestamp: deadLetter.FailedAt,
+	}, nil
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks the error of a callback that fails whatever the
+// number of attempts, like a message that can not be decoded. The
+// subscriber sends its message to the dead-letter topic at once.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether the error was marked by Permanent.
+func IsPermanent(err error) bool {
+	var permanent permanentError
+	return errors.As(err, &permanent)
+}
+
+// Replay publishes the original message of the dead letters read from
+// consumer to publisher, until limit messages are replayed, no message
+// arrives for idle or the context is done. A zero limit replays every
+// dead letter. The messages the consumer read past the limit, and
+// committed already, are replayed too before Replay returns. It returns
+// the number of replayed messages.
+func Replay(ctx context.Context, consumer pkg.Consumer, publisher pkg.Publisher, limit int, idle time.Duration, log logger.Logger) (int, error) {
+	readCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	chMsg := make(chan pkg.Message)
+	chErr := make(chan error)
+	reading := make(chan struct{})
+	go func() {
+		defer close(reading)
+		consumer.Read(readCtx, chMsg, chErr)
+	}()
+
+	var replayed int
+	replay := func(m pkg.Message) error {
+		var deadLetter DeadLetter
+		if err := json.Unmarshal(m.RawData, &deadLetter); err != nil {
+			log.Errorf("dead letter %s skipped, it can not be decoded: %s", m.EventID, err.Error())
+			return nil
+		}
+
+		if err := publisher.Publish(ctx, deadLetter.Message); err != nil {
+			return err
+		}
+
+		log.Infof("message %s replayed, it failed %d times: %s", deadLetter.Message.EventID, deadLetter.Attempts, deadLetter.Error)
+		replayed++
+
+		return nil
+	}
+
+	// read replays messages until limit messages are replayed, no
+	// message arrives for idle or the context is done.
+	read := func() error {
+		for limit == 0 || replayed < limit {
+			select {
+			case m := <-chMsg:
+				if err := replay(m); err != nil {
+					return err
+				}
+			case err := <-chErr:
+				return err
+			case <-time.After(idle):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		return nil
+	}
+
+	err := read()
+
+	// the consumer stops once its context is done, what it read
+	// meanwhile is drained so that no message is lost.
+	cancel()
+	for {
+		select {
+		case m := <-chMsg:
+			if replayErr := replay(m); replayErr != nil {
+				log.Errorf("message %s not replayed: %s", m.EventID, replayErr.Error())
+				if err == nil {
+					err = replayErr
+				}
+			}
+		case <-chErr:
+		case <-reading:
+			return replayed, err
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/big_queue/pkg"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+)
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, policy.backoff(1))
+	assert.Equal(t, 2*time.Second, policy.backoff(2))
+	assert.Equal(t, 4*time.Second, policy.backoff(3))
+	assert.Equal(t, 5*time.Second, policy.backoff(4))
+}
+
+func TestPermanent(t *testing.T) {
+	err := errors.New("invalid batch")
+	assert.True(t, IsPermanent(Permanent(err)))
+	assert.ErrorIs(t, Permanent(err), err)
+	assert.False(t, IsPermanent(err))
+	assert.NoError(t, Permanent(nil))
+}
+
+func TestReplay(t *testing.T) {
+	tests := []struct {
+		name         string
+		limit        int
+		wantReplayed int
+	}{
+		{name: "every dead letter", wantReplayed: 3},
+		{name: "up to the limit", limit: 2, wantReplayed: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			deadLetters, topic := NewQueue(), NewQueue()
+			for _, id := range []string{"1", "2", "3"} {
+				msg, err := newDeadLetterMessage(DeadLetter{
+					Message:  Message{EventID: id, RawData: []byte(`{"provider":"brentford"}`)},
+					Error:    "unknown error",
+					Attempts: 3,
+					FailedAt: time.Now().UTC(),
+				})
+				require.NoError(t, err)
+				require.NoError(t, deadLetters.Publish(ctx, msg))
+
+				// a message that is not a dead letter is skipped
+				require.NoError(t, deadLetters.Publish(ctx, Message{EventID: "invalid", RawData: []byte(`[]`)}))
+			}
+
+			replayed, err := Replay(ctx, deadLetters, topic, tt.limit, 50*time.Millisecond, logger.New())
+			require.NoError(t, err)
+			// the queue may have read past the limit, that message is
+			// replayed too
+			assert.GreaterOrEqual(t, replayed, tt.wantReplayed)
+
+			require.Len(t, topic.messages, replayed)
+			for _, id := range []string{"1", "2", "3"}[:replayed] {
+				m := <-topic.messages
+				assert.Equal(t, id, m.EventID)
+				assert.Equal(t, []byte(`{"provider":"brentford"}`), m.RawData)
+			}
+
+			// no dead letter is lost
+			left := 0
+			for len(deadLetters.messages) > 0 {
+				if m := <-deadLetters.messages; m.EventID != "invalid" {
+					left++
+				}
+			}
+			assert.Equal(t, 3, replayed+left)
+		})
+	}
+}
+
+// pastLimitConsumer delivers its messages whether or not the context is
+// done, like a consumer that read and committed them already.
+type pastLimitConsumer struct {
+	messages []pkg.Message
+}
+
+func (c *pastLimitConsumer) Read(_ context.Context, chMsg chan pkg.Message, _ chan error) {
+	for _, m := range c.messages {
+		chMsg <- m
+	}
+}
+
+func TestReplay_pastLimit(t *testing.T) {
+	ctx := context.Background()
+	consumer := &pastLimitConsumer{}
+	for _, id := range []string{"1", "2", "3"} {
+		msg, err := newDeadLetterMessage(DeadLetter{
+			Message:  Message{EventID: id, RawData: []byte(`{"provider":"brentford"}`)},
+			Error:    "unknown error",
+			Attempts: 3,
+			FailedAt: time.Now().UTC(),
+		})
+		require.NoError(t, err)
+		consumer.messages = append(consumer.messages, pkg.Message{EventID: msg.EventID, RawData: msg.RawData})
+	}
+
+	topic := NewQueue()
+	replayed, err := Replay(ctx, consumer, topic, 2, time.Minute, logger.New())
+	require.NoError(t, err)
+	// the message read past the limit is replayed rather than lost
+	assert.Equal(t, 3, replayed)
+	require.Len(t, topic.messages, 3)
+	for _, id := range []string{"1", "2", "3"} {
+		assert.Equal(t, id, (<-topic.messages).EventID)
+	}
+}
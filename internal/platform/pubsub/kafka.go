@@ -0,0 +1,82 @@
+package pubsub
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/patriciabonaldy/big_queue/pkg"
+	"github.com/patriciabonaldy/big_queue/pkg/kafka"
+)
+
+// kafkaPublisher is the big_queue publisher of a kafka topic.
+type kafkaPublisher struct {
+	pkg.Publisher
+}
+
+// kafkaConsumer is the big_queue consumer of a kafka topic, which commits
+// the offsets of the messages as it reads them.
+type kafkaConsumer struct {
+	pkg.Consumer
+
+	mu sync.Mutex
+	// reading is closed when Read returns, it is nil before Read runs.
+	reading chan struct{}
+}
+
+var (
+	_ Publisher = &kafkaPublisher{}
+	_ Consumer  = &kafkaConsumer{}
+)
+
+// NewKafkaPublisher returns the publisher of the topic.
+func NewKafkaPublisher(brokers []string, topic string) Publisher {
+	return &kafkaPublisher{Publisher: kafka.NewPublisher(brokers, topic)}
+}
+
+// NewKafkaConsumer returns the consumer of the topic.
+func NewKafkaConsumer(brokers []string, topic string) Consumer {
+	return newKafkaConsumer(kafka.NewConsumer(brokers, topic))
+}
+
+func newKafkaConsumer(consumer pkg.Consumer) *kafkaConsumer {
+	return &kafkaConsumer{Consumer: consumer}
+}
+
+// Close closes the writer of the publisher when big_queue exposes it.
+func (p *kafkaPublisher) Close() error {
+	return closeIfCloser(p.Publisher)
+}
+
+func (c *kafkaConsumer) Read(ctx context.Context, chMsg chan pkg.Message, chErr chan error) {
+	reading := make(chan struct{})
+	c.mu.Lock()
+	c.reading = reading
+	c.mu.Unlock()
+
+	defer close(reading)
+	c.Consumer.Read(ctx, chMsg, chErr)
+}
+
+// Close waits for Read to return, so that the offsets of the messages read
+// are committed before the process exits, and closes the reader of the
+// consumer when big_queue exposes it. The context of Read must be done.
+func (c *kafkaConsumer) Close() error {
+	c.mu.Lock()
+	reading := c.reading
+	c.mu.Unlock()
+
+	if reading != nil {
+		<-reading
+	}
+
+	return closeIfCloser(c.Consumer)
+}
+
+func closeIfCloser(v interface{}) error {
+	if closer, ok := v.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package pubsub
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/patriciabonaldy/big_queue/pkg"
+)
+
+// committingConsumer commits its offsets when it is closed.
+type committingConsumer struct {
+	committed int32
+}
+
+func (c *committingConsumer) Read(ctx context.Context, _ chan pkg.Message, _ chan error) {
+	<-ctx.Done()
+}
+
+func (c *committingConsumer) Close() error {
+	atomic.StoreInt32(&c.committed, 1)
+	return nil
+}
+
+func Test_kafkaConsumer_Close(t *testing.T) {
+	committing := &committingConsumer{}
+	consumer := newKafkaConsumer(committing)
+	ctx, cancel := context.WithCancel(context.Background())
+	reading := make(chan struct{})
+	go func() {
+		defer close(reading)
+		consumer.Read(ctx, make(chan pkg.Message), make(chan error))
+	}()
+
+	closed := make(chan error)
+	time.AfterFunc(10*time.Millisecond, func() { closed <- consumer.Close() })
+
+	// it waits for Read to return
+	select {
+	case <-closed:
+		t.Fatal("Close returned while the consumer was reading")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	<-reading
+	select {
+	case err := <-closed:
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&committing.committed))
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return after Read returned")
+	}
+}
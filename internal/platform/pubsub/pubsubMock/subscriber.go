@@ -17,3 +17,8 @@ type Subscriber struct {
 func (_m *Subscriber) Subscriber(ctx context.Context, callback func(context.Context, interface{}) error) {
 	_m.Called(ctx, callback)
 }
+
+// Stop provides a mock function with given fields:
+func (_m *Subscriber) Stop() {
+	_m.Called()
+}
@@ -0,0 +1,91 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/patriciabonaldy/big_queue/pkg"
+)
+
+// Publisher is the publisher of a topic, Close flushes the messages it
+// still holds.
+type Publisher interface {
+	pkg.Publisher
+	io.Closer
+}
+
+// Consumer is the consumer of a topic, Close commits the offsets of the
+// messages it read when the topic has offsets.
+type Consumer interface {
+	pkg.Consumer
+	io.Closer
+}
+
+// queueSize is how many messages the in-process queue holds before
+// Publish blocks.
+const queueSize = 100
+
+// Queue is an in-process topic, both the publisher of the syncers and
+// the consumer of the news subscriber, for deployments without Kafka.
+// Messages are lost when the process stops.
+type Queue struct {
+	messages chan pkg.Message
+}
+
+var (
+	_ Publisher = &Queue{}
+	_ Consumer  = &Queue{}
+)
+
+// NewQueue returns an empty in-process queue.
+func NewQueue() *Queue {
+	return &Queue{messages: make(chan pkg.Message, queueSize)}
+}
+
+// Publish queues the message as the Kafka publisher would send it, it
+// blocks while the queue is full.
+func (q *Queue) Publish(ctx context.Context, message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	var msg pkg.Message
+	if err = json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	select {
+	case q.messages <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Read sends the queued messages to chMsg until the context is done. A
+// message taken when the context is done goes back to the queue.
+func (q *Queue) Read(ctx context.Context, chMsg chan pkg.Message, _ chan error) {
+	for {
+		select {
+		case msg := <-q.messages:
+			select {
+			case chMsg <- msg:
+			case <-ctx.Done():
+				select {
+				case q.messages <- msg:
+				default:
+				}
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Close does nothing, the messages left in the queue are lost.
+func (q *Queue) Close() error {
+	return nil
+}
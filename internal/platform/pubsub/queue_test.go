@@ -0,0 +1,33 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/big_queue/pkg"
+)
+
+func TestQueue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	queue := NewQueue()
+	message := Message{EventID: "1", RawData: []byte(`{"provider":"brentford"}`), Timestamp: time.Now()}
+	require.NoError(t, queue.Publish(ctx, message))
+
+	chMsg := make(chan pkg.Message)
+	done := make(chan struct{})
+	go func() {
+		queue.Read(ctx, chMsg, nil)
+		close(done)
+	}()
+
+	got := <-chMsg
+	assert.Equal(t, "1", got.EventID)
+	assert.Equal(t, message.RawData, got.RawData)
+
+	cancel()
+	<-done
+}
@@ -2,7 +2,9 @@ package pubsub
 
 import (
 	"context"
-	"log"
+	"encoding/json"
+	"sync"
+	"time"
 
 	"github.com/patriciabonaldy/big_queue/pkg"
 	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
@@ -10,41 +12,156 @@ import (
 
 type subscriber struct {
 	consumer pkg.Consumer
-	log      logger.Logger
+	policy   RetryPolicy
+	// deadLetters is the publisher of the dead-letter topic, the messages
+	// that keep failing are dropped without it.
+	deadLetters pkg.Publisher
+	log         logger.Logger
+
+	// stop is closed by Stop, once.
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 type Subscriber interface {
 	Subscriber(ctx context.Context, callback func(ctx context.Context, message interface{}) error)
+	Stop()
 }
 
 //go:generate mockery --case=snake --outpkg=pubsubMock --output=pubsubMock --name=Subscriber
 
-func NewSubscriber(consumer pkg.Consumer, log logger.Logger) Subscriber {
+// NewSubscriber returns a subscriber that retries the messages whose
+// callback fails with the policy, and then publishes them to deadLetters.
+// deadLetters can be nil.
+func NewSubscriber(consumer pkg.Consumer, policy RetryPolicy, deadLetters pkg.Publisher, log logger.Logger) Subscriber {
+	if policy.Attempts < 1 {
+		policy.Attempts = 1
+	}
+
 	p := subscriber{
-		consumer: consumer,
-		log:      log,
+		consumer:    consumer,
+		policy:      policy,
+		deadLetters: deadLetters,
+		log:         log,
+		stop:        make(chan struct{}),
 	}
 
 	return &p
 }
 
-func (s subscriber) Subscriber(ctx context.Context, callback func(ctx context.Context, message interface{}) error) {
+// Subscriber runs the callback of the messages read by the consumer until
+// Stop is called or the context is done, and the consumer stopped
+// reading. Every message the consumer read is processed, also the ones
+// read while it was stopping, since a consumer may have committed them
+// already. Canceling the context cancels the message in flight.
+func (s *subscriber) Subscriber(ctx context.Context, callback func(ctx context.Context, message interface{}) error) {
+	read, stopReading := context.WithCancel(ctx)
+	defer stopReading()
+	go func() {
+		select {
+		case <-s.stop:
+			stopReading()
+		case <-read.Done():
+		}
+	}()
+
 	chMsg := make(chan pkg.Message)
 	chErr := make(chan error)
+	done := make(chan struct{})
 	go func() {
-		s.consumer.Read(ctx, chMsg, chErr)
+		defer close(done)
+		s.consumer.Read(read, chMsg, chErr)
 	}()
 
 	// read/process message
 	for {
 		select {
 		case m := <-chMsg:
-			err := callback(ctx, m)
-			if err != nil {
-				log.Println(err)
-			}
+			s.handle(ctx, callback, m)
 		case err := <-chErr:
-			log.Println(err)
+			s.log.Errorf("reading messages: %s", err.Error())
+		case <-done:
+			return
+		}
+	}
+}
+
+// Stop stops reading messages, Subscriber returns once the message in
+// flight and the ones the consumer still hands over are processed.
+func (s *subscriber) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// handle runs the callback of the message with the retry policy, the
+// message goes to the dead-letter topic when it still fails. It is left
+// alone when the subscriber stops.
+func (s *subscriber) handle(ctx context.Context, callback func(ctx context.Context, message interface{}) error, m pkg.Message) {
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		if err = callback(ctx, m); err == nil {
+			return
+		}
+
+		if IsPermanent(err) || attempt >= s.policy.Attempts || ctx.Err() != nil {
+			break
+		}
+
+		s.log.Infof("message %s failed, attempt %d of %d: %s", m.EventID, attempt, s.policy.Attempts, err.Error())
+		select {
+		case <-time.After(s.policy.backoff(attempt)):
+		case <-ctx.Done():
+		}
+	}
+
+	if ctx.Err() != nil {
+		s.log.Errorf("message %s not processed, the subscriber stopped: %s", m.EventID, err.Error())
+		return
+	}
+
+	s.deadLetter(ctx, m, err, attempt)
+}
+
+func (s *subscriber) deadLetter(ctx context.Context, m pkg.Message, err error, attempts int) {
+	if s.deadLetters == nil {
+		s.log.Errorf("message %s dropped after %d attempts: %s", m.EventID, attempts, err.Error())
+		return
+	}
+
+	message, errMsg := messageOf(m)
+	if errMsg == nil {
+		var msg Message
+		msg, errMsg = newDeadLetterMessage(DeadLetter{
+			Message:   message,
+			Error:     err.Error(),
+			Attempts:  attempts,
+			Permanent: IsPermanent(err),
+			FailedAt:  time.Now().UTC(),
+		})
+		if errMsg == nil {
+			errMsg = s.deadLetters.Publish(ctx, msg)
 		}
 	}
+
+	if errMsg != nil {
+		s.log.Errorf("message %s dropped after %d attempts, it can not be dead-lettered: %s: %s",
+			m.EventID, attempts, err.Error(), errMsg.Error())
+		return
+	}
+
+	s.log.Errorf("message %s dead-lettered after %d attempts: %s", m.EventID, attempts, err.Error())
+}
+
+// messageOf returns the message as it was read, with its timestamp, in the
+// format every topic is published with.
+func messageOf(m pkg.Message) (Message, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return Message{}, err
+	}
+
+	var msg Message
+	err = json.Unmarshal(data, &msg)
+
+	return msg, err
 }
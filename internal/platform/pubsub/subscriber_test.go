@@ -0,0 +1,144 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/big_queue/pkg"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+)
+
+func Test_subscriber_handle(t *testing.T) {
+	failure := errors.New("unknown error")
+	tests := []struct {
+		name         string
+		failures     int
+		err          error
+		wantCalls    int
+		wantDead     bool
+		wantAttempts int
+	}{
+		{name: "processed", wantCalls: 1},
+		{name: "processed on a retry", failures: 2, err: failure, wantCalls: 3},
+		{name: "attempts run out", failures: 5, err: failure, wantCalls: 3, wantDead: true, wantAttempts: 3},
+		{name: "permanent error", failures: 5, err: Permanent(failure), wantCalls: 1, wantDead: true, wantAttempts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deadLetters := NewQueue()
+			policy := RetryPolicy{Attempts: 3, Backoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+			s := NewSubscriber(NewQueue(), policy, deadLetters, logger.New()).(*subscriber)
+
+			var calls int
+			callback := func(context.Context, interface{}) error {
+				calls++
+				if calls <= tt.failures {
+					return tt.err
+				}
+
+				return nil
+			}
+
+			message := pkg.Message{EventID: "1", RawData: []byte(`{"provider":"brentford"}`)}
+			s.handle(context.Background(), callback, message)
+			assert.Equal(t, tt.wantCalls, calls)
+
+			select {
+			case m := <-deadLetters.messages:
+				require.True(t, tt.wantDead, "unexpected dead letter")
+				var got DeadLetter
+				require.NoError(t, json.Unmarshal(m.RawData, &got))
+				assert.Equal(t, "1", m.EventID)
+				// the whole message is kept, not only its data
+				want, err := messageOf(message)
+				require.NoError(t, err)
+				assert.Equal(t, want, got.Message)
+				assert.Equal(t, message.RawData, got.Message.RawData)
+				assert.Equal(t, "unknown error", got.Error)
+				assert.Equal(t, tt.wantAttempts, got.Attempts)
+				assert.Equal(t, IsPermanent(tt.err), got.Permanent)
+			default:
+				assert.False(t, tt.wantDead, "missing dead letter")
+			}
+		})
+	}
+}
+
+func Test_subscriber_handle_canceled(t *testing.T) {
+	deadLetters := NewQueue()
+	s := NewSubscriber(NewQueue(), RetryPolicy{Attempts: 3, Backoff: time.Hour}, deadLetters, logger.New()).(*subscriber)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls int
+	s.handle(ctx, func(context.Context, interface{}) error {
+		calls++
+		cancel()
+		return context.Canceled
+	}, pkg.Message{EventID: "1"})
+
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, deadLetters.messages)
+}
+
+func Test_subscriber_Subscriber(t *testing.T) {
+	queue := NewQueue()
+	s := NewSubscriber(queue, RetryPolicy{Attempts: 1}, nil, logger.New())
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, queue.Publish(ctx, Message{EventID: "1"}))
+
+	processed := make(chan string, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Subscriber(ctx, func(_ context.Context, message interface{}) error {
+			processed <- message.(pkg.Message).EventID
+			return nil
+		})
+	}()
+
+	assert.Equal(t, "1", <-processed)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Subscriber did not return after the context was canceled")
+	}
+}
+
+// stoppingConsumer hands over a message it read while it was stopping,
+// as a consumer that commits on read may.
+type stoppingConsumer struct{}
+
+func (stoppingConsumer) Read(ctx context.Context, chMsg chan pkg.Message, _ chan error) {
+	<-ctx.Done()
+	chMsg <- pkg.Message{EventID: "1"}
+}
+
+func Test_subscriber_Stop(t *testing.T) {
+	s := NewSubscriber(stoppingConsumer{}, RetryPolicy{Attempts: 1}, nil, logger.New())
+
+	var processed []string
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Subscriber(context.Background(), func(_ context.Context, message interface{}) error {
+			processed = append(processed, message.(pkg.Message).EventID)
+			return nil
+		})
+	}()
+
+	s.Stop()
+	select {
+	case <-done:
+		assert.Equal(t, []string{"1"}, processed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Subscriber did not return after Stop")
+	}
+}
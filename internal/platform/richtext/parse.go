@@ -0,0 +1,310 @@
+package richtext
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+)
+
+// node is an element or, when tag is empty, a text of the parsed markup.
+type node struct {
+	tag      string
+	attrs    map[string]string
+	text     string
+	children []*node
+}
+
+// Parse converts the HTML (or XHTML) body of an article to a Body.
+// Unknown tags are unwrapped, scripts and styles are dropped, and so are
+// links and sources with unsafe schemes. Malformed markup is parsed up
+// to the first error.
+func Parse(markup string) internal.Body {
+	b := &builder{space: true}
+	b.walk(parseTree(markup))
+	b.flush()
+
+	return internal.Body{Blocks: b.blocks}
+}
+
+// Text converts markup to plain text.
+func Text(markup string) string {
+	return Parse(markup).Text()
+}
+
+// parseTree returns the root element that wraps the markup.
+func parseTree(markup string) *node {
+	document := &node{}
+	decoder := xml.NewDecoder(strings.NewReader("<root>" + markup + "</root>"))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	stack := []*node{document}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		parent := stack[len(stack)-1]
+		switch t := token.(type) {
+		case xml.StartElement:
+			stack = closeImplied(stack, strings.ToLower(t.Name.Local))
+			parent = stack[len(stack)-1]
+			n := &node{tag: strings.ToLower(t.Name.Local), attrs: make(map[string]string, len(t.Attr))}
+			for _, attr := range t.Attr {
+				n.attrs[strings.ToLower(attr.Name.Local)] = attr.Value
+			}
+
+			parent.children = append(parent.children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			tag := strings.ToLower(t.Name.Local)
+			for i := len(stack) - 1; i > 1; i-- {
+				if stack[i].tag == tag {
+					stack = stack[:i]
+					break
+				}
+			}
+		case xml.CharData:
+			parent.children = append(parent.children, &node{text: string(t)})
+		}
+	}
+
+	if len(document.children) == 0 {
+		return document
+	}
+
+	return document.children[0]
+}
+
+// closeImplied closes the elements that HTML ends without an end tag
+// when tag starts: paragraphs before a block and list items before an
+// item of the same list.
+func closeImplied(stack []*node, tag string) []*node {
+	closes := map[string]bool{"p": blockTags[tag], "li": tag == "li"}
+	for i := len(stack) - 1; i > 1; i-- {
+		switch {
+		case closes[stack[i].tag]:
+			return stack[:i]
+		case blockTags[stack[i].tag] || stack[i].tag == "li":
+			return stack
+		}
+	}
+
+	return stack
+}
+
+var blockTags = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "header": true, "footer": true,
+	"ul": true, "ol": true, "figure": true, "table": true, "blockquote": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// builder collects the blocks of a tree, inlines are kept pending until
+// a block element closes the current paragraph.
+type builder struct {
+	blocks  []internal.Block
+	pending []internal.Inline
+	// space reports whether the last text written ended with a space,
+	// so that runs of white space are collapsed across elements.
+	space bool
+}
+
+func (b *builder) walk(n *node) {
+	for _, child := range n.children {
+		b.block(child)
+	}
+}
+
+func (b *builder) block(n *node) {
+	switch n.tag {
+	case "":
+		b.pending = append(b.pending, b.inlines(n)...)
+	case "p", "div", "section", "article", "header", "footer", "li", "ul", "ol", "figure", "figcaption", "table", "tr", "td", "th":
+		b.flush()
+		b.walk(n)
+		b.flush()
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		b.flush()
+		level, _ := strconv.Atoi(n.tag[1:])
+		b.pending = b.inlines(n)
+		b.flushAs(internal.Block{Type: internal.BlockHeading, Level: level})
+	case "blockquote":
+		b.flush()
+		b.pending = b.inlines(n)
+		b.flushAs(internal.Block{Type: internal.BlockQuote})
+	case "img":
+		b.flush()
+		if src := n.attrs["src"]; internal.IsSafeURL(src, false) {
+			b.blocks = append(b.blocks, internal.Block{Type: internal.BlockImage, Src: src, Alt: collapse(n.attrs["alt"])})
+		}
+	case "iframe", "video", "audio", "embed", "object":
+		b.flush()
+		if src := embedSource(n); internal.IsSafeURL(src, false) {
+			b.blocks = append(b.blocks, internal.Block{Type: internal.BlockEmbed, Src: src})
+		}
+	case "script", "style", "noscript", "head", "title":
+	default:
+		b.pending = append(b.pending, b.inlines(n)...)
+	}
+}
+
+func (b *builder) inlines(n *node) []internal.Inline {
+	switch n.tag {
+	case "":
+		text := b.text(n.text)
+		if text == "" {
+			return nil
+		}
+
+		return []internal.Inline{{Type: internal.InlineText, Text: text}}
+	case "a":
+		children := b.children(n)
+		href := n.attrs["href"]
+		if len(children) == 0 || !internal.IsSafeURL(href, true) {
+			return children
+		}
+
+		return []internal.Inline{{Type: internal.InlineLink, Href: href, Children: children}}
+	case "em", "i":
+		return wrap(internal.InlineEmphasis, b.children(n))
+	case "strong", "b":
+		return wrap(internal.InlineStrong, b.children(n))
+	case "br":
+		b.space = true
+		return []internal.Inline{{Type: internal.InlineLineBreak}}
+	case "p", "div":
+		// paragraphs inside quotes are separated by line breaks.
+		children := b.children(n)
+		if len(children) == 0 {
+			return nil
+		}
+
+		b.space = true
+		return append(children, internal.Inline{Type: internal.InlineLineBreak})
+	case "script", "style", "noscript", "img", "iframe", "video", "audio", "embed", "object":
+		return nil
+	default:
+		return b.children(n)
+	}
+}
+
+func (b *builder) children(n *node) []internal.Inline {
+	var inlines []internal.Inline
+	for _, child := range n.children {
+		inlines = append(inlines, b.inlines(child)...)
+	}
+
+	return inlines
+}
+
+// text collapses white space like browsers do.
+func (b *builder) text(value string) string {
+	var sb strings.Builder
+	for _, r := range value {
+		if unicode.IsSpace(r) {
+			if !b.space {
+				sb.WriteRune(' ')
+				b.space = true
+			}
+
+			continue
+		}
+
+		sb.WriteRune(r)
+		b.space = false
+	}
+
+	return sb.String()
+}
+
+func (b *builder) flush() {
+	b.flushAs(internal.Block{Type: internal.BlockParagraph})
+}
+
+func (b *builder) flushAs(block internal.Block) {
+	inlines := trim(b.pending)
+	b.pending = nil
+	b.space = true
+	if len(inlines) == 0 {
+		return
+	}
+
+	block.Inlines = inlines
+	b.blocks = append(b.blocks, block)
+}
+
+// trim removes the white space and line breaks at both ends of inlines.
+func trim(inlines []internal.Inline) []internal.Inline {
+	for len(inlines) > 0 {
+		last := &inlines[len(inlines)-1]
+		if last.Type == internal.InlineLineBreak {
+			inlines = inlines[:len(inlines)-1]
+			continue
+		}
+
+		if last.Type == internal.InlineText {
+			last.Text = strings.TrimRightFunc(last.Text, unicode.IsSpace)
+			if last.Text == "" {
+				inlines = inlines[:len(inlines)-1]
+				continue
+			}
+		} else {
+			last.Children = trim(last.Children)
+		}
+
+		break
+	}
+
+	for len(inlines) > 0 {
+		first := &inlines[0]
+		if first.Type == internal.InlineLineBreak {
+			inlines = inlines[1:]
+			continue
+		}
+
+		if first.Type == internal.InlineText {
+			first.Text = strings.TrimLeftFunc(first.Text, unicode.IsSpace)
+			if first.Text == "" {
+				inlines = inlines[1:]
+				continue
+			}
+		}
+
+		break
+	}
+
+	return inlines
+}
+
+func wrap(t internal.InlineType, children []internal.Inline) []internal.Inline {
+	if len(children) == 0 {
+		return nil
+	}
+
+	return []internal.Inline{{Type: t, Children: children}}
+}
+
+func embedSource(n *node) string {
+	for _, attr := range []string{"src", "data"} {
+		if n.attrs[attr] != "" {
+			return n.attrs[attr]
+		}
+	}
+
+	for _, child := range n.children {
+		if child.tag == "source" && child.attrs["src"] != "" {
+			return child.attrs["src"]
+		}
+	}
+
+	return ""
+}
+
+func collapse(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
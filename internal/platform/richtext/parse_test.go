@@ -0,0 +1,95 @@
+package richtext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+)
+
+func text(value string) internal.Inline {
+	return internal.Inline{Type: internal.InlineText, Text: value}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		markup string
+		want   []internal.Block
+	}{
+		{
+			name:   "empty",
+			markup: "  ",
+		},
+		{
+			name:   "plain text",
+			markup: "  All the results\n from the weekend. ",
+			want: []internal.Block{
+				{Type: internal.BlockParagraph, Inlines: []internal.Inline{text("All the results from the weekend.")}},
+			},
+		},
+		{
+			name:   "paragraphs with mixed text and links",
+			markup: `<p>Mads Bidstrup captained Denmark.</p><p>Read the full report on <a href="https://www.dbu.dk">the DBU website</a>.</p>`,
+			want: []internal.Block{
+				{Type: internal.BlockParagraph, Inlines: []internal.Inline{text("Mads Bidstrup captained Denmark.")}},
+				{Type: internal.BlockParagraph, Inlines: []internal.Inline{
+					text("Read the full report on "),
+					{Type: internal.InlineLink, Href: "https://www.dbu.dk", Children: []internal.Inline{text("the DBU website")}},
+					text("."),
+				}},
+			},
+		},
+		{
+			name:   "emphasis, strong and line breaks",
+			markup: `<p><b>Full time</b>: Brentford 2-0 <i>Arsenal</i><br/>Goals: Canós, Nørgaard</p>`,
+			want: []internal.Block{
+				{Type: internal.BlockParagraph, Inlines: []internal.Inline{
+					{Type: internal.InlineStrong, Children: []internal.Inline{text("Full time")}},
+					text(": Brentford 2-0 "),
+					{Type: internal.InlineEmphasis, Children: []internal.Inline{text("Arsenal")}},
+					{Type: internal.InlineLineBreak},
+					text("Goals: Canós, Nørgaard"),
+				}},
+			},
+		},
+		{
+			name: "headings, quotes, images and embeds",
+			markup: `<h3>Reaction</h3><blockquote><p>We were brilliant</p></blockquote>
+				<figure><img src="https://example.com/a.jpg" alt=" Thomas  Frank "></figure>
+				<iframe src="https://www.youtube.com/embed/xyz"></iframe>`,
+			want: []internal.Block{
+				{Type: internal.BlockHeading, Level: 3, Inlines: []internal.Inline{text("Reaction")}},
+				{Type: internal.BlockQuote, Inlines: []internal.Inline{text("We were brilliant")}},
+				{Type: internal.BlockImage, Src: "https://example.com/a.jpg", Alt: "Thomas Frank"},
+				{Type: internal.BlockEmbed, Src: "https://www.youtube.com/embed/xyz"},
+			},
+		},
+		{
+			name:   "unsafe markup is dropped",
+			markup: `<p onclick="x()">Hi <a href="javascript:alert(1)">there</a><script>alert(1)</script></p><img src="data:image/png;base64,AA">`,
+			want: []internal.Block{
+				{Type: internal.BlockParagraph, Inlines: []internal.Inline{text("Hi "), text("there")}},
+			},
+		},
+		{
+			name:   "html entities and unclosed tags",
+			markup: `<p>Tickets&nbsp;&amp; hospitality<p>On sale now`,
+			want: []internal.Block{
+				{Type: internal.BlockParagraph, Inlines: []internal.Inline{text("Tickets & hospitality")}},
+				{Type: internal.BlockParagraph, Inlines: []internal.Inline{text("On sale now")}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.markup)
+			assert.Equal(t, tt.want, got.Blocks)
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	assert.Equal(t, "The boss on Sunday's game.\n\nSee you there", Text("<p>The boss on <b>Sunday&apos;s</b> game.</p> See you there"))
+}
@@ -0,0 +1,238 @@
+package search
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+)
+
+const (
+	maxHighlights = 3
+	wordsBefore   = 5
+	wordsAfter    = 10
+)
+
+// Weights of every searchable field, shared by the storage text indexes
+// so that every implementation ranks the results alike.
+var Weights = map[string]int{
+	"title":       10,
+	"subtitle":    5,
+	"teaser_text": 3,
+	"body_text":   1,
+}
+
+// Field is a searchable text of an article.
+type Field struct {
+	Name string
+	Text string
+}
+
+type word struct {
+	start, end int
+	match      bool
+}
+
+// Fields returns the searchable fields of the article, ordered by weight.
+func Fields(article internal.ArticleNews) []Field {
+	return []Field{
+		{Name: "title", Text: article.Title},
+		{Name: "subtitle", Text: article.Subtitle},
+		{Name: "teaser_text", Text: article.TeaserText},
+		{Name: "body_text", Text: article.BodyText},
+	}
+}
+
+// Terms splits text into lowercase search terms.
+func Terms(text string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, term := range strings.FieldsFunc(strings.ToLower(text), isSeparator) {
+		if len(term) < 2 || seen[term] {
+			continue
+		}
+
+		seen[term] = true
+		terms = append(terms, term)
+	}
+
+	return terms
+}
+
+// Score returns the weighted count of the words of the fields starting
+// with one of the terms, 0 means that the article does not match.
+func Score(terms []string, fields []Field) float64 {
+	var score float64
+	for _, field := range fields {
+		for _, w := range words(field.Text, terms) {
+			if w.match {
+				score += float64(Weights[field.Name])
+			}
+		}
+	}
+
+	return score
+}
+
+// Rank scores the articles against the query, best matches first, for the
+// storages without a text index.
+func Rank(articles []internal.ArticleNews, query internal.SearchQuery) ([]internal.SearchResult, error) {
+	terms := Terms(query.Text)
+	if len(terms) == 0 {
+		return nil, internal.ErrInvalidSearch
+	}
+
+	var results []internal.SearchResult
+	for _, article := range articles {
+		fields := Fields(article)
+		score := Score(terms, fields)
+		if score == 0 {
+			continue
+		}
+
+		results = append(results, internal.SearchResult{
+			Article:    article,
+			Score:      score,
+			Highlights: Highlight(terms, fields),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+
+		return results[i].Article.NewsID < results[j].Article.NewsID
+	})
+
+	if query.Limit > 0 && len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+
+	return results, nil
+}
+
+// Visible returns the articles that are neither deleted nor unpublished,
+// the only ones that can be found.
+func Visible(articles []internal.ArticleNews) []internal.ArticleNews {
+	visible := make([]internal.ArticleNews, 0, len(articles))
+	for _, article := range articles {
+		if !article.IsHidden() {
+			visible = append(visible, article)
+		}
+	}
+
+	return visible
+}
+
+// Highlight returns HTML-escaped snippets of the fields around the first
+// match of the terms, with the matching words wrapped in <em> tags.
+func Highlight(terms []string, fields []Field) []string {
+	var highlights []string
+	for _, field := range fields {
+		if len(highlights) == maxHighlights {
+			break
+		}
+
+		if snippet, ok := highlight(field.Text, terms); ok {
+			highlights = append(highlights, snippet)
+		}
+	}
+
+	return highlights
+}
+
+func highlight(text string, terms []string) (string, bool) {
+	ws := words(text, terms)
+	first := -1
+	for i, w := range ws {
+		if w.match {
+			first = i
+			break
+		}
+	}
+
+	if first < 0 {
+		return "", false
+	}
+
+	from := first - wordsBefore
+	if from < 0 {
+		from = 0
+	}
+
+	to := first + wordsAfter
+	if to > len(ws)-1 {
+		to = len(ws) - 1
+	}
+
+	var sb strings.Builder
+	pos := 0
+	if from > 0 {
+		sb.WriteString("…")
+		pos = ws[from].start
+	}
+
+	for _, w := range ws[from : to+1] {
+		sb.WriteString(html.EscapeString(text[pos:w.start]))
+		if w.match {
+			sb.WriteString("<em>" + html.EscapeString(text[w.start:w.end]) + "</em>")
+		} else {
+			sb.WriteString(html.EscapeString(text[w.start:w.end]))
+		}
+
+		pos = w.end
+	}
+
+	if to < len(ws)-1 {
+		sb.WriteString("…")
+	} else {
+		sb.WriteString(html.EscapeString(text[pos:]))
+	}
+
+	return sb.String(), true
+}
+
+func words(text string, terms []string) []word {
+	var ws []word
+	start := -1
+	for i, r := range text {
+		if isSeparator(r) {
+			if start >= 0 {
+				ws = append(ws, newWord(text, start, i, terms))
+				start = -1
+			}
+
+			continue
+		}
+
+		if start < 0 {
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		ws = append(ws, newWord(text, start, len(text), terms))
+	}
+
+	return ws
+}
+
+func newWord(text string, start, end int, terms []string) word {
+	w := word{start: start, end: end}
+	lower := strings.ToLower(text[start:end])
+	for _, term := range terms {
+		if strings.HasPrefix(lower, term) {
+			w.match = true
+			break
+		}
+	}
+
+	return w
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
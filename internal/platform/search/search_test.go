@@ -0,0 +1,38 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerms(t *testing.T) {
+	assert.Equal(t, []string{"toney", "goal"}, Terms("Toney's goal, TONEY"))
+	assert.Empty(t, Terms(" - "))
+}
+
+func TestScore(t *testing.T) {
+	fields := []Field{
+		{Name: "title", Text: "Toney scores again"},
+		{Name: "body_text", Text: "Ivan Toney scored twice, Toney said"},
+	}
+
+	assert.Equal(t, float64(12), Score([]string{"toney"}, fields))
+	assert.Equal(t, float64(11), Score([]string{"score"}, fields))
+	assert.Equal(t, float64(0), Score([]string{"mbeumo"}, fields))
+}
+
+func TestHighlight(t *testing.T) {
+	body := strings.Repeat("word ", 10) + "Toney & <Mbeumo> " + strings.Repeat("word ", 20)
+	fields := []Field{
+		{Name: "title", Text: "Pre-season schedule"},
+		{Name: "body_text", Text: body},
+	}
+
+	got := Highlight([]string{"toney", "mbeumo"}, fields)
+	want := []string{
+		"…word word word word word <em>Toney</em> &amp; &lt;<em>Mbeumo</em>&gt; word word word word word word word word word…",
+	}
+	assert.Equal(t, want, got)
+}
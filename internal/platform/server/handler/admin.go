@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminKey is the key of the gin context set for requests of an admin.
+const adminKey = "admin"
+
+// Admin is a middleware that marks the requests carrying the admin
+// token as "Authorization: Bearer <token>". Without a token nobody is
+// an admin.
+func Admin(token string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		bearer := strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		if token != "" && subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1 {
+			ctx.Set(adminKey, true)
+		}
+
+		ctx.Next()
+	}
+}
+
+// RequireAdmin is a middleware that rejects the requests that Admin did
+// not mark.
+func RequireAdmin() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !isAdmin(ctx) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"msg": "the admin token is required"})
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func isAdmin(ctx *gin.Context) bool {
+	return ctx.GetBool(adminKey)
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -24,11 +26,70 @@ func New(service business.Service, log logger.Logger) ArticleHandler {
 
 func (a *ArticleHandler) GetArticles() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		ans, err := a.service.GetArticles(ctx)
+		var req RequestArticles
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"msg": err.Error()})
+			return
+		}
+
+		if req.IncludeHidden && !isAdmin(ctx) {
+			ctx.JSON(http.StatusForbidden, gin.H{"msg": "include_hidden requires the admin token"})
+			return
+		}
+
+		criteria := business.Criteria{
+			Limit:     req.Limit,
+			Cursor:    req.Cursor,
+			Sort:      req.Sort,
+			Club:      req.Club,
+			Taxonomy:  req.Taxonomy,
+			Published: req.Published,
+			From:      req.From,
+			To:        req.To,
+
+			IncludeHidden:   req.IncludeHidden,
+			IncludeArchived: req.IncludeArchived,
+		}
+		ans, err := a.service.GetArticles(ctx, criteria)
 		if err != nil {
 			switch err {
 			case internal.ErrIDIsEmpty,
-				internal.ErrInvalidData:
+				internal.ErrInvalidData,
+				internal.ErrInvalidLimit,
+				internal.ErrInvalidSort,
+				internal.ErrInvalidCursor,
+				internal.ErrInvalidFilter,
+				internal.ErrHiddenFilter:
+				ctx.JSON(http.StatusBadRequest, err.Error())
+				return
+
+			default:
+				ctx.JSON(http.StatusInternalServerError, err.Error())
+				return
+			}
+		}
+
+		if ans.Next != "" {
+			ctx.Header("Link", nextLink(ctx, ans.Next))
+		}
+
+		ctx.JSON(http.StatusOK, toResponsePage(ans))
+	}
+}
+
+func (a *ArticleHandler) SearchArticles() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req RequestSearch
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"msg": err.Error()})
+			return
+		}
+
+		ans, err := a.service.Search(ctx, req.Q, req.Limit)
+		if err != nil {
+			switch err {
+			case internal.ErrInvalidSearch,
+				internal.ErrInvalidLimit:
 				ctx.JSON(http.StatusBadRequest, err.Error())
 				return
 
@@ -38,7 +99,7 @@ func (a *ArticleHandler) GetArticles() gin.HandlerFunc {
 			}
 		}
 
-		ctx.JSON(http.StatusOK, toResponseArticles(ans))
+		ctx.JSON(http.StatusOK, toResponseSearch(ans))
 	}
 }
 
@@ -55,7 +116,24 @@ func (a *ArticleHandler) GetArticleByID() gin.HandlerFunc {
 			return
 		}
 
-		ans, err := a.service.GetArticleByID(ctx, req.ID)
+		var reqFormat RequestFormat
+		if err := ctx.ShouldBindQuery(&reqFormat); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"msg": err.Error()})
+			return
+		}
+
+		var format internal.BodyFormat
+		if reqFormat.Format != "" && reqFormat.Format != "json" {
+			f, err := internal.ParseBodyFormat(reqFormat.Format)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, err.Error())
+				return
+			}
+
+			format = f
+		}
+
+		ans, err := a.service.GetArticleByID(ctx, req.ID, isAdmin(ctx))
 		if err != nil {
 			switch err {
 			case internal.ErrIDIsEmpty,
@@ -70,10 +148,58 @@ func (a *ArticleHandler) GetArticleByID() gin.HandlerFunc {
 			}
 		}
 
+		if format != "" {
+			ctx.Data(http.StatusOK, bodyContentTypes[format], []byte(ans.RichBody().Render(format)))
+			return
+		}
+
 		ctx.JSON(http.StatusOK, toResponse(ans))
 	}
 }
 
+var bodyContentTypes = map[internal.BodyFormat]string{
+	internal.FormatHTML:     "text/html; charset=utf-8",
+	internal.FormatMarkdown: "text/markdown; charset=utf-8",
+	internal.FormatText:     "text/plain; charset=utf-8",
+}
+
+// nextLink builds the RFC 8288 Link header pointing to the next page,
+// keeping the rest of the query parameters of the request.
+func nextLink(ctx *gin.Context, next string) string {
+	u := *ctx.Request.URL
+	values := u.Query()
+	values.Set("cursor", next)
+	u.RawQuery = values.Encode()
+
+	return fmt.Sprintf(`<%s>; rel="next"`, u.RequestURI())
+}
+
+func toResponsePage(page *internal.ArticlePage) ResponseArticles {
+	resp := ResponseArticles{
+		Data: toResponseArticles(page.Articles),
+		Pagination: Pagination{
+			Limit: page.Limit,
+			Sort:  page.Sort.String(),
+			Next:  page.Next,
+		},
+	}
+
+	return resp
+}
+
+func toResponseSearch(results []internal.SearchResult) ResponseSearch {
+	resp := ResponseSearch{Data: make([]SearchHit, 0, len(results))}
+	for _, r := range results {
+		resp.Data = append(resp.Data, SearchHit{
+			Response:   toResponse(&r.Article),
+			Score:      r.Score,
+			Highlights: r.Highlights,
+		})
+	}
+
+	return resp
+}
+
 func toResponseArticles(articleNews []internal.ArticleNews) []Response {
 	var resp = make([]Response, 0, 1)
 	for _, a := range articleNews {
@@ -84,6 +210,29 @@ func toResponseArticles(articleNews []internal.ArticleNews) []Response {
 }
 
 func toResponse(articleNews *internal.ArticleNews) Response {
+	var body *internal.Body
+	if !articleNews.Body.IsEmpty() {
+		body = &articleNews.Body
+	}
+
+	var publishDate *time.Time
+	if !articleNews.PublishDate.IsZero() {
+		date := articleNews.PublishDate.UTC()
+		publishDate = &date
+	}
+
+	var deletedAt *time.Time
+	if articleNews.IsDeleted() {
+		date := articleNews.DeletedAt.UTC()
+		deletedAt = &date
+	}
+
+	var archivedAt *time.Time
+	if articleNews.IsArchived() {
+		date := articleNews.ArchivedAt.UTC()
+		archivedAt = &date
+	}
+
 	return Response{
 		NewsID:            articleNews.NewsID,
 		ClubName:          articleNews.ClubName,
@@ -92,13 +241,17 @@ func toResponse(articleNews *internal.ArticleNews) Response {
 		Title:             articleNews.Title,
 		Subtitle:          articleNews.Subtitle,
 		BodyText:          articleNews.BodyText,
+		Body:              body,
 		GalleryImageURLs:  articleNews.GalleryImageURLs,
 		VideoURL:          articleNews.VideoURL,
 		Taxonomies:        articleNews.Taxonomies,
 		TeaserText:        articleNews.TeaserText,
 		ThumbnailImageURL: articleNews.ThumbnailImageURL,
-		PublishDate:       articleNews.PublishDate,
+		PublishDate:       publishDate,
+		LastUpdateDate:    articleNews.LastUpdateDate,
 		IsPublished:       articleNews.IsPublished,
 		CreateAt:          articleNews.CreateAt,
+		DeletedAt:         deletedAt,
+		ArchivedAt:        archivedAt,
 	}
 }
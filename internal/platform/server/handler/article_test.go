@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -16,6 +17,7 @@ import (
 	"github.com/patriciabonaldy/sports-news/internal"
 	"github.com/patriciabonaldy/sports-news/internal/business"
 	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/memory"
 	"github.com/patriciabonaldy/sports-news/internal/platform/storage/storagemocks"
 )
 
@@ -94,11 +96,17 @@ func TestHandler_GetArticleByID(t *testing.T) {
 
 func TestHandler_GetArticles(t *testing.T) {
 	repositoryMock := new(storagemocks.Storage)
-	repositoryMock.On("GetArticles", mock.Anything).
+	repositoryMock.On("GetArticlesPage", mock.Anything, mock.Anything).
 		Return(nil, errors.New("something unexpected happened")).Once()
 
-	repositoryMock.On("GetArticles", mock.Anything).
-		Return([]internal.ArticleNews{mockArticle()}, nil).Once()
+	next := internal.Cursor{Sort: "-publish_date", Key: "2022-06-15 08:00:00", ID: "641838"}.Encode()
+	repositoryMock.On("GetArticlesPage", mock.Anything, mock.Anything).
+		Return(&internal.ArticlePage{
+			Articles: []internal.ArticleNews{mockArticle()},
+			Limit:    1,
+			Sort:     internal.DefaultSort(),
+			Next:     next,
+		}, nil).Once()
 	log := logger.New()
 	svc := business.NewService(repositoryMock, log)
 	handler := New(svc, log)
@@ -106,6 +114,45 @@ func TestHandler_GetArticles(t *testing.T) {
 	r := gin.New()
 	r.GET("/articles", handler.GetArticles())
 
+	t.Run("given a invalid limit it returns 400", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/articles?limit=abc", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("given a invalid sort it returns 400", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/articles?sort=title", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("given a invalid filter it returns 400", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/articles?published=maybe&from=2022-06-01", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
 	t.Run("given a error it returns 500", func(t *testing.T) {
 		req, err := http.NewRequest(http.MethodGet, "/articles", nil)
 		require.NoError(t, err)
@@ -120,7 +167,7 @@ func TestHandler_GetArticles(t *testing.T) {
 	})
 
 	t.Run("given a valid request it returns 200", func(t *testing.T) {
-		req, err := http.NewRequest(http.MethodGet, "/articles", nil)
+		req, err := http.NewRequest(http.MethodGet, "/articles?limit=1", nil)
 		require.NoError(t, err)
 
 		rec := httptest.NewRecorder()
@@ -129,7 +176,7 @@ func TestHandler_GetArticles(t *testing.T) {
 		res := rec.Result()
 		defer res.Body.Close()
 
-		var resp []Response
+		var resp ResponseArticles
 		err = json.NewDecoder(res.Body).Decode(&resp)
 		require.NoError(t, err)
 
@@ -147,10 +194,171 @@ func TestHandler_GetArticles(t *testing.T) {
 			},
 		}
 		assert.Equal(t, http.StatusOK, res.StatusCode)
-		assert.Equal(t, len(resp), len(want))
+		assert.Equal(t, len(resp.Data), len(want))
+		assert.Equal(t, next, resp.Pagination.Next)
+		assert.Equal(t, "-publish_date", resp.Pagination.Sort)
+		assert.Equal(t, `</articles?cursor=`+next+`&limit=1>; rel="next"`, res.Header.Get("Link"))
 	})
 }
 
+func TestHandler_GetArticles_includeHidden(t *testing.T) {
+	repository := memory.NewStorage()
+	ctx := context.Background()
+	published := mockArticle()
+	unpublished := mockArticle()
+	unpublished.NewsID = "641839"
+	unpublished.IsPublished = false
+	deleted := mockArticle()
+	deleted.NewsID = "641840"
+	deleted.DeletedAt = timeN
+	for _, article := range []internal.ArticleNews{published, unpublished, deleted} {
+		require.NoError(t, repository.Save(ctx, article))
+	}
+
+	log := logger.New()
+	handler := New(business.NewService(repository, log), log)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Admin("secret"))
+	r.GET("/articles", handler.GetArticles())
+
+	tests := []struct {
+		name       string
+		url        string
+		token      string
+		wantStatus int
+		wantIDs    []string
+	}{
+		{
+			name:       "hidden articles are left out by default",
+			url:        "/articles",
+			wantStatus: http.StatusOK,
+			wantIDs:    []string{"641838"},
+		},
+		{
+			name:       "include_hidden without the admin token",
+			url:        "/articles?include_hidden=true",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "include_hidden with a wrong token",
+			url:        "/articles?include_hidden=true",
+			token:      "guess",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "include_hidden with the admin token",
+			url:        "/articles?include_hidden=true&sort=publish_date",
+			token:      "secret",
+			wantStatus: http.StatusOK,
+			wantIDs:    []string{"641838", "641839", "641840"},
+		},
+		{
+			name:       "unpublished without include_hidden",
+			url:        "/articles?published=false",
+			token:      "secret",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantIDs == nil {
+				return
+			}
+
+			var resp ResponseArticles
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			var ids []string
+			for _, article := range resp.Data {
+				ids = append(ids, article.NewsID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestHandler_GetArticleByID_hidden(t *testing.T) {
+	repository := memory.NewStorage()
+	ctx := context.Background()
+	deleted := mockArticle()
+	deleted.DeletedAt = timeN
+	require.NoError(t, repository.Save(ctx, deleted))
+
+	log := logger.New()
+	handler := New(business.NewService(repository, log), log)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Admin("secret"))
+	r.GET("/articles/:id", handler.GetArticleByID())
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{name: "without the admin token", wantStatus: http.StatusBadRequest},
+		{name: "with the admin token", token: "secret", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/articles/"+deleted.NewsID, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_GetArticles_includeArchived(t *testing.T) {
+	repository := memory.NewStorage()
+	ctx := context.Background()
+	archived := mockArticle()
+	archived.PublishDate = time.Date(2021, 6, 15, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, repository.Save(ctx, archived))
+	recent := mockArticle()
+	recent.NewsID = "641839"
+	recent.PublishDate = time.Date(2022, 6, 15, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, repository.Save(ctx, recent))
+	_, err := repository.Archive(ctx, internal.ArchiveQuery{Before: recent.PublishDate, At: timeN})
+	require.NoError(t, err)
+
+	log := logger.New()
+	handler := New(business.NewService(repository, log), log)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/articles", handler.GetArticles())
+
+	for url, want := range map[string][]string{
+		"/articles":                       {"641839"},
+		"/articles?include_archived=true": {"641839", "641838"},
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		require.Equal(t, http.StatusOK, rec.Code, url)
+
+		var resp ResponseArticles
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		var ids []string
+		for _, article := range resp.Data {
+			ids = append(ids, article.NewsID)
+			assert.Equal(t, article.NewsID == "641838", article.ArchivedAt != nil, url)
+		}
+		assert.Equal(t, want, ids, url)
+	}
+}
+
 func mockArticle() internal.ArticleNews {
 	return internal.ArticleNews{
 		NewsID:         "641838",
@@ -164,3 +372,85 @@ func mockArticle() internal.ArticleNews {
 		CreateAt:       timeN,
 	}
 }
+
+func TestHandler_GetArticleByID_format(t *testing.T) {
+	article := mockArticle()
+	article.Body = internal.Body{Blocks: []internal.Block{{
+		Type: internal.BlockParagraph,
+		Inlines: []internal.Inline{
+			{Type: internal.InlineText, Text: "Read the "},
+			{Type: internal.InlineLink, Href: "https://www.dbu.dk", Children: []internal.Inline{{Type: internal.InlineText, Text: "report"}}},
+		},
+	}}}
+	article.BodyText = article.Body.Text()
+
+	repositoryMock := new(storagemocks.Storage)
+	repositoryMock.On("GetArticleByID", mock.Anything, "641838").Return(&article, nil)
+	log := logger.New()
+	handler := New(business.NewService(repositoryMock, log), log)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/articles/:id", handler.GetArticleByID())
+
+	tests := []struct {
+		name            string
+		format          string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "html",
+			format:          "html",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/html; charset=utf-8",
+			wantBody:        `<p>Read the <a href="https://www.dbu.dk" rel="nofollow noopener noreferrer">report</a></p>`,
+		},
+		{
+			name:            "markdown",
+			format:          "markdown",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/markdown; charset=utf-8",
+			wantBody:        "Read the [report](https://www.dbu.dk)",
+		},
+		{
+			name:            "text",
+			format:          "text",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "Read the report",
+		},
+		{
+			name:       "unknown format",
+			format:     "pdf",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/articles/641838?format="+tt.format, nil)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, tt.wantContentType, rec.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantBody, rec.Body.String())
+		})
+	}
+
+	t.Run("json carries the structured body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/articles/641838", nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		var resp Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, "Read the report", resp.BodyText)
+		require.NotNil(t, resp.Body)
+		assert.Equal(t, article.Body, *resp.Body)
+	})
+}
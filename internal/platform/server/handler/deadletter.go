@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/business"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+)
+
+// DeadLetterHandler serves the admin endpoints of the dead letters.
+type DeadLetterHandler struct {
+	service business.DeadLetterService
+	log     logger.Logger
+}
+
+func NewDeadLetterHandler(service business.DeadLetterService, log logger.Logger) DeadLetterHandler {
+	return DeadLetterHandler{
+		service: service,
+		log:     log,
+	}
+}
+
+func (d *DeadLetterHandler) GetDeadLetters() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ans, err := d.service.GetDeadLetters(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponseDeadLetters(ans))
+	}
+}
+
+func (d *DeadLetterHandler) RetryDeadLetter() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req RequestDeadLetter
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"msg": err.Error()})
+			return
+		}
+
+		if err := d.service.RetryDeadLetter(ctx, req.Provider, req.ID); err != nil {
+			deadLetterError(ctx, err)
+			return
+		}
+
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+func (d *DeadLetterHandler) DropDeadLetter() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req RequestDeadLetter
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"msg": err.Error()})
+			return
+		}
+
+		if err := d.service.DropDeadLetter(ctx, req.Provider, req.ID); err != nil {
+			deadLetterError(ctx, err)
+			return
+		}
+
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+func deadLetterError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, internal.ErrDeadLetterNotFound):
+		ctx.JSON(http.StatusNotFound, err.Error())
+
+	case errors.Is(err, internal.ErrRetryFailed):
+		ctx.JSON(http.StatusBadGateway, err.Error())
+
+	default:
+		ctx.JSON(http.StatusInternalServerError, err.Error())
+	}
+}
+
+func toResponseDeadLetters(deadLetters []internal.DeadLetter) ResponseDeadLetters {
+	resp := ResponseDeadLetters{Data: make([]ResponseDeadLetter, 0, len(deadLetters))}
+	for _, d := range deadLetters {
+		resp.Data = append(resp.Data, ResponseDeadLetter{
+			ArticleID:   d.ArticleID,
+			Provider:    d.Provider,
+			ArticleURL:  d.ArticleURL,
+			IsPublished: d.IsPublished,
+			Stage:       d.Stage,
+			Error:       d.Error,
+			Attempts:    d.Attempts,
+			FailedAt:    d.FailedAt,
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/business"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/memory"
+)
+
+// retrier deletes the dead letters it retries, as the pipeline does once
+// the article is stored, or fails with err.
+type retrier struct {
+	repository internal.Storage
+	err        error
+}
+
+func (r retrier) Retry(ctx context.Context, deadLetter internal.DeadLetter) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	return r.repository.DeleteDeadLetter(ctx, deadLetter.Provider, deadLetter.ArticleID)
+}
+
+func TestHandler_DeadLetters(t *testing.T) {
+	deadLetter := internal.DeadLetter{
+		ArticleID:   "641838",
+		Provider:    "brentford",
+		ArticleURL:  "https://www.brentfordfc.com/api/incrowd/getnewsarticleinformation?id=%s",
+		IsPublished: true,
+		Stage:       "fetch",
+		Error:       "unknown error",
+		Attempts:    3,
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		url        string
+		token      string
+		retryErr   error
+		wantStatus int
+		wantDead   int
+	}{
+		{name: "without the admin token", method: http.MethodGet, url: "/admin/dead-letters", wantStatus: http.StatusForbidden, wantDead: 1},
+		{name: "list", method: http.MethodGet, url: "/admin/dead-letters", token: "secret", wantStatus: http.StatusOK, wantDead: 1},
+		{name: "retry", method: http.MethodPost, url: "/admin/dead-letters/brentford/641838/retry", token: "secret", wantStatus: http.StatusNoContent},
+		{
+			name:       "retry failed again",
+			method:     http.MethodPost,
+			url:        "/admin/dead-letters/brentford/641838/retry",
+			token:      "secret",
+			retryErr:   errors.New("unknown error"),
+			wantStatus: http.StatusBadGateway,
+			wantDead:   1,
+		},
+		{name: "retry unknown", method: http.MethodPost, url: "/admin/dead-letters/brentford/1/retry", token: "secret", wantStatus: http.StatusNotFound, wantDead: 1},
+		{name: "drop", method: http.MethodDelete, url: "/admin/dead-letters/brentford/641838", token: "secret", wantStatus: http.StatusNoContent},
+		{name: "drop unknown", method: http.MethodDelete, url: "/admin/dead-letters/brentford/1", token: "secret", wantStatus: http.StatusNotFound, wantDead: 1},
+		{name: "drop of another provider", method: http.MethodDelete, url: "/admin/dead-letters/arsenal/641838", token: "secret", wantStatus: http.StatusNotFound, wantDead: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := memory.NewStorage()
+			require.NoError(t, repository.SaveDeadLetter(context.Background(), deadLetter))
+
+			log := logger.New()
+			svc := business.NewDeadLetterService(repository, retrier{repository: repository, err: tt.retryErr}, log)
+			handler := NewDeadLetterHandler(svc, log)
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(Admin("secret"))
+			admin := r.Group("/admin/dead-letters", RequireAdmin())
+			admin.GET("", handler.GetDeadLetters())
+			admin.POST("/:provider/:id/retry", handler.RetryDeadLetter())
+			admin.DELETE("/:provider/:id", handler.DropDeadLetter())
+
+			req, err := http.NewRequest(tt.method, tt.url, nil)
+			require.NoError(t, err)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, res.StatusCode)
+			if tt.wantStatus == http.StatusOK {
+				var resp ResponseDeadLetters
+				require.NoError(t, json.NewDecoder(res.Body).Decode(&resp))
+				require.Len(t, resp.Data, 1)
+				assert.Equal(t, "641838", resp.Data[0].ArticleID)
+				assert.Equal(t, 3, resp.Data[0].Attempts)
+			}
+
+			deadLetters, err := repository.GetDeadLetters(context.Background())
+			require.NoError(t, err)
+			assert.Len(t, deadLetters, tt.wantDead)
+		})
+	}
+}
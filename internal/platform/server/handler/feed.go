@@ -0,0 +1,490 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/business"
+)
+
+const (
+	feedTitle       = "Sports news"
+	jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+	atomNamespace   = "http://www.w3.org/2005/Atom"
+
+	contentTypeRSS      = "application/rss+xml; charset=utf-8"
+	contentTypeAtom     = "application/atom+xml; charset=utf-8"
+	contentTypeJSONFeed = "application/feed+json; charset=utf-8"
+)
+
+// feed is the page of published articles served by the feed endpoints.
+type feed struct {
+	title    string
+	selfURL  string
+	homeURL  string
+	baseURL  string
+	updated  time.Time
+	articles []internal.ArticleNews
+}
+
+// FeedRSS serves the latest published articles as RSS 2.0.
+func (a *ArticleHandler) FeedRSS() gin.HandlerFunc {
+	return a.serveFeed("rss", contentTypeRSS, func(f feed) ([]byte, error) {
+		return marshalXML(toRSS(f))
+	})
+}
+
+// FeedAtom serves the latest published articles as Atom 1.0.
+func (a *ArticleHandler) FeedAtom() gin.HandlerFunc {
+	return a.serveFeed("atom", contentTypeAtom, func(f feed) ([]byte, error) {
+		return marshalXML(toAtom(f))
+	})
+}
+
+// FeedJSON serves the latest published articles as JSON Feed 1.1.
+func (a *ArticleHandler) FeedJSON() gin.HandlerFunc {
+	return a.serveFeed("json", contentTypeJSONFeed, func(f feed) ([]byte, error) {
+		return json.Marshal(toJSONFeed(f))
+	})
+}
+
+// serveFeed reads the page of articles and answers 304 Not Modified when
+// the client already has it, according to its ETag or Last-Modified.
+func (a *ArticleHandler) serveFeed(format, contentType string, render func(feed) ([]byte, error)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req RequestFeed
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"msg": err.Error()})
+			return
+		}
+
+		criteria := business.Criteria{
+			Limit:     req.Limit,
+			Club:      req.Club,
+			Taxonomy:  req.Taxonomy,
+			Published: "true",
+		}
+		ans, err := a.service.GetArticles(ctx, criteria)
+		if err != nil {
+			switch err {
+			case internal.ErrInvalidLimit,
+				internal.ErrInvalidFilter:
+				ctx.JSON(http.StatusBadRequest, err.Error())
+				return
+
+			default:
+				ctx.JSON(http.StatusInternalServerError, err.Error())
+				return
+			}
+		}
+
+		f := newFeed(ctx, req, ans.Articles)
+		etag := feedETag(format, ans.Articles)
+		ctx.Header("ETag", etag)
+		ctx.Header("Cache-Control", "no-cache")
+		if !f.updated.IsZero() {
+			ctx.Header("Last-Modified", f.updated.Format(http.TimeFormat))
+		}
+
+		if notModified(ctx.Request, etag, f.updated) {
+			ctx.Status(http.StatusNotModified)
+			return
+		}
+
+		data, err := render(f)
+		if err != nil {
+			a.log.Errorf("error render %s feed %s", format, err)
+			ctx.JSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		ctx.Data(http.StatusOK, contentType, data)
+	}
+}
+
+func newFeed(ctx *gin.Context, req RequestFeed, articles []internal.ArticleNews) feed {
+	baseURL := requestBaseURL(ctx.Request)
+	title := feedTitle
+	if req.Club != "" {
+		title += " - " + req.Club
+	}
+
+	if req.Taxonomy != "" {
+		title += " - " + req.Taxonomy
+	}
+
+	f := feed{
+		title:    title,
+		selfURL:  baseURL + ctx.Request.URL.RequestURI(),
+		homeURL:  baseURL + "/articles",
+		baseURL:  baseURL,
+		articles: articles,
+	}
+	for _, article := range articles {
+		if updated := articleUpdated(article); updated.After(f.updated) {
+			f.updated = updated
+		}
+	}
+
+	return f
+}
+
+// feedETag is a weak validator of the articles of the feed, it changes
+// whenever an article is added, removed or updated.
+func feedETag(format string, articles []internal.ArticleNews) string {
+	h := sha256.New()
+	h.Write([]byte(format))
+	for _, article := range articles {
+		h.Write([]byte{0})
+		h.Write([]byte(article.NewsID))
+		h.Write([]byte{0})
+		h.Write([]byte(article.Hash()))
+		h.Write([]byte{0})
+		h.Write([]byte(article.LastUpdateDate))
+	}
+
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// notModified follows RFC 7232: If-None-Match takes precedence over
+// If-Modified-Since.
+func notModified(r *http.Request, etag string, updated time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || updated.IsZero() {
+		return false
+	}
+
+	return !updated.Truncate(time.Second).After(since)
+}
+
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	return scheme + "://" + r.Host
+}
+
+// articleUpdated is when the club last updated the article, or else
+// published it, or else when it was stored.
+func articleUpdated(article internal.ArticleNews) time.Time {
+	if t, ok := parsePublishDate(article.LastUpdateDate); ok {
+		return t
+	}
+
+	if !article.PublishDate.IsZero() {
+		return article.PublishDate
+	}
+
+	return article.CreateAt.UTC()
+}
+
+func parsePublishDate(value string) (time.Time, bool) {
+	t, err := internal.ParsePublishDate(value)
+	return t, err == nil && !t.IsZero()
+}
+
+// articleLink is the page of the article on the website of the club,
+// or else the article in this API.
+func (f feed) articleLink(article internal.ArticleNews) string {
+	if article.ArticleURL != "" {
+		return article.ArticleURL
+	}
+
+	return f.baseURL + "/articles/" + article.NewsID
+}
+
+func (f feed) articleID(article internal.ArticleNews) string {
+	return f.baseURL + "/articles/" + article.NewsID
+}
+
+func summary(article internal.ArticleNews) string {
+	if article.TeaserText != "" {
+		return article.TeaserText
+	}
+
+	return article.Subtitle
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Self          atomLink  `xml:"atom:link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        rssGUID       `xml:"guid"`
+	Description string        `xml:"description,omitempty"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	Categories  []string      `xml:"category"`
+	Enclosure   *rssEnclosure `xml:"enclosure"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// rssEnclosure leaves out the length, the size of the images is unknown.
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr,omitempty"`
+	Type   string `xml:"type,attr"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	XMLNS   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Author     atomAuthor     `xml:"author"`
+	Summary    string         `xml:"summary,omitempty"`
+	Content    *atomContent   `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	Summary       string           `json:"summary,omitempty"`
+	ContentHTML   string           `json:"content_html,omitempty"`
+	ContentText   string           `json:"content_text,omitempty"`
+	Image         string           `json:"image,omitempty"`
+	DatePublished string           `json:"date_published,omitempty"`
+	DateModified  string           `json:"date_modified,omitempty"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+func toRSS(f feed) rssFeed {
+	doc := rssFeed{
+		Version: "2.0",
+		Atom:    atomNamespace,
+		Channel: rssChannel{
+			Title:       f.title,
+			Link:        f.homeURL,
+			Self:        atomLink{Href: f.selfURL, Rel: "self", Type: "application/rss+xml"},
+			Description: "Latest published news of the clubs",
+			Items:       make([]rssItem, 0, len(f.articles)),
+		},
+	}
+	if !f.updated.IsZero() {
+		doc.Channel.LastBuildDate = f.updated.Format(time.RFC1123Z)
+	}
+
+	for _, article := range f.articles {
+		item := rssItem{
+			Title:       article.Title,
+			Link:        f.articleLink(article),
+			GUID:        rssGUID{Value: f.articleID(article)},
+			Description: summary(article),
+			Categories:  article.Taxonomies,
+		}
+		if published := article.PublishDate; !published.IsZero() {
+			item.PubDate = published.UTC().Format(time.RFC1123Z)
+		}
+
+		if article.ThumbnailImageURL != "" {
+			item.Enclosure = &rssEnclosure{URL: article.ThumbnailImageURL, Type: imageType(article.ThumbnailImageURL)}
+		}
+
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+
+	return doc
+}
+
+func toAtom(f feed) atomFeed {
+	updated := f.updated
+	if updated.IsZero() {
+		updated = time.Now().UTC()
+	}
+
+	doc := atomFeed{
+		XMLNS:   atomNamespace,
+		ID:      f.selfURL,
+		Title:   f.title,
+		Updated: updated.Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.selfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: f.homeURL, Rel: "alternate"},
+		},
+		Entries: make([]atomEntry, 0, len(f.articles)),
+	}
+
+	for _, article := range f.articles {
+		entryUpdated := articleUpdated(article)
+		if entryUpdated.IsZero() {
+			entryUpdated = updated
+		}
+
+		entry := atomEntry{
+			ID:      f.articleID(article),
+			Title:   article.Title,
+			Links:   []atomLink{{Href: f.articleLink(article), Rel: "alternate"}},
+			Updated: entryUpdated.Format(time.RFC3339),
+			Author:  atomAuthor{Name: article.ClubName, URI: article.ClubWebsiteURL},
+			Summary: summary(article),
+		}
+		if published := article.PublishDate; !published.IsZero() {
+			entry.Published = published.UTC().Format(time.RFC3339)
+		}
+
+		if content := article.RichBody().HTML(); content != "" {
+			entry.Content = &atomContent{Type: "html", Value: content}
+		}
+
+		if article.ThumbnailImageURL != "" {
+			entry.Links = append(entry.Links, atomLink{
+				Href: article.ThumbnailImageURL,
+				Rel:  "enclosure",
+				Type: imageType(article.ThumbnailImageURL),
+			})
+		}
+
+		for _, t := range article.Taxonomies {
+			entry.Categories = append(entry.Categories, atomCategory{Term: t})
+		}
+
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return doc
+}
+
+func toJSONFeed(f feed) jsonFeed {
+	doc := jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       f.title,
+		HomePageURL: f.homeURL,
+		FeedURL:     f.selfURL,
+		Items:       make([]jsonFeedItem, 0, len(f.articles)),
+	}
+
+	for _, article := range f.articles {
+		item := jsonFeedItem{
+			ID:           article.NewsID,
+			URL:          f.articleLink(article),
+			Title:        article.Title,
+			Summary:      summary(article),
+			ContentHTML:  article.RichBody().HTML(),
+			Image:        article.ThumbnailImageURL,
+			DateModified: articleUpdated(article).Format(time.RFC3339),
+			Tags:         article.Taxonomies,
+		}
+		if item.ContentHTML == "" {
+			item.ContentText = item.Summary
+		}
+
+		if published := article.PublishDate; !published.IsZero() {
+			item.DatePublished = published.UTC().Format(time.RFC3339)
+		}
+
+		if article.ClubName != "" {
+			item.Authors = []jsonFeedAuthor{{Name: article.ClubName, URL: article.ClubWebsiteURL}}
+		}
+
+		doc.Items = append(doc.Items, item)
+	}
+
+	return doc
+}
+
+func imageType(url string) string {
+	switch ext := strings.ToLower(url[strings.LastIndex(url, ".")+1:]); ext {
+	case "png", "gif", "webp":
+		return "image/" + ext
+	default:
+		return "image/jpeg"
+	}
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
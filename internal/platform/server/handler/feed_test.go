@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/business"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/memory"
+)
+
+func TestHandler_Feeds(t *testing.T) {
+	repository := memory.NewStorage()
+	pontus := mockArticle()
+	pontus.Taxonomies = []string{"Interviews"}
+	pontus.ThumbnailImageURL = "https://www.brentfordfc.com/images/pontus.png"
+	pontus.PublishDate = time.Date(2022, 6, 15, 8, 0, 0, 0, time.UTC)
+	pontus.LastUpdateDate = "2022-06-15 08:00:21"
+	require.NoError(t, repository.Save(context.Background(), pontus))
+
+	toney := mockArticle()
+	toney.NewsID = "641900"
+	toney.ClubName = "England"
+	toney.Title = "Toney called up by England"
+	toney.PublishDate = time.Date(2022, 6, 14, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, repository.Save(context.Background(), toney))
+
+	draft := mockArticle()
+	draft.NewsID = "641901"
+	draft.IsPublished = false
+	require.NoError(t, repository.Save(context.Background(), draft))
+
+	log := logger.New()
+	handler := New(business.NewService(repository, log), log)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/feeds/rss", handler.FeedRSS())
+	r.GET("/feeds/atom", handler.FeedAtom())
+	r.GET("/feeds/json", handler.FeedJSON())
+
+	serve := func(t *testing.T, url string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	t.Run("rss lists the published articles", func(t *testing.T) {
+		rec := serve(t, "/feeds/rss", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, contentTypeRSS, rec.Header().Get("Content-Type"))
+		assert.Equal(t, "Wed, 15 Jun 2022 08:00:21 GMT", rec.Header().Get("Last-Modified"))
+
+		var doc rssFeed
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+		require.Len(t, doc.Channel.Items, 2)
+		item := doc.Channel.Items[0]
+		assert.Equal(t, "Pontus explains", item.Title)
+		assert.Equal(t, pontus.ArticleURL, item.Link)
+		assert.Equal(t, "Wed, 15 Jun 2022 08:00:00 +0000", item.PubDate)
+		assert.Equal(t, []string{"Interviews"}, item.Categories)
+		require.NotNil(t, item.Enclosure)
+		assert.Equal(t, "image/png", item.Enclosure.Type)
+		assert.NotContains(t, rec.Body.String(), "length=")
+	})
+
+	t.Run("atom filters by club", func(t *testing.T) {
+		rec := serve(t, "/feeds/atom?club=England", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, contentTypeAtom, rec.Header().Get("Content-Type"))
+
+		var doc atomFeed
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+		assert.Equal(t, "Sports news - England", doc.Title)
+		require.Len(t, doc.Entries, 1)
+		assert.Equal(t, "http://example.com/articles/641900", doc.Entries[0].ID)
+		assert.Equal(t, "2022-06-14T10:00:00Z", doc.Entries[0].Published)
+	})
+
+	t.Run("json feed filters by taxonomy", func(t *testing.T) {
+		rec := serve(t, "/feeds/json?taxonomy=Interviews", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, contentTypeJSONFeed, rec.Header().Get("Content-Type"))
+
+		var doc jsonFeed
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+		assert.Equal(t, jsonFeedVersion, doc.Version)
+		assert.Equal(t, "http://example.com/feeds/json?taxonomy=Interviews", doc.FeedURL)
+		require.Len(t, doc.Items, 1)
+		assert.Equal(t, "641838", doc.Items[0].ID)
+		assert.Equal(t, "2022-06-15T08:00:21Z", doc.Items[0].DateModified)
+		assert.Equal(t, []jsonFeedAuthor{{Name: "Brentford", URL: "https://www.brentfordfc.com"}}, doc.Items[0].Authors)
+	})
+
+	t.Run("given a matching etag it returns 304", func(t *testing.T) {
+		etag := serve(t, "/feeds/rss", nil).Header().Get("ETag")
+		require.NotEmpty(t, etag)
+
+		rec := serve(t, "/feeds/rss", map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.Bytes())
+
+		rec = serve(t, "/feeds/atom", map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("given if-modified-since it returns 304 until an article changes", func(t *testing.T) {
+		headers := map[string]string{"If-Modified-Since": "Wed, 15 Jun 2022 08:00:21 GMT"}
+		assert.Equal(t, http.StatusNotModified, serve(t, "/feeds/json", headers).Code)
+
+		headers["If-Modified-Since"] = "Wed, 15 Jun 2022 08:00:20 GMT"
+		assert.Equal(t, http.StatusOK, serve(t, "/feeds/json", headers).Code)
+	})
+
+	t.Run("given an invalid limit it returns 400", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, serve(t, "/feeds/rss?limit=1000", nil).Code)
+	})
+}
+
+func Test_articleUpdated(t *testing.T) {
+	createAt := time.Date(2022, 6, 13, 9, 30, 0, 0, time.UTC)
+	publishDate := time.Date(2022, 6, 14, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		article func() internal.ArticleNews
+		want    time.Time
+	}{
+		{
+			name: "last update date",
+			article: func() internal.ArticleNews {
+				article := mockArticle()
+				article.LastUpdateDate = "2022-06-15 08:00:21"
+				article.PublishDate = publishDate
+				return article
+			},
+			want: time.Date(2022, 6, 15, 8, 0, 21, 0, time.UTC),
+		},
+		{
+			name: "publish date",
+			article: func() internal.ArticleNews {
+				article := mockArticle()
+				article.LastUpdateDate = ""
+				article.PublishDate = publishDate
+				return article
+			},
+			want: publishDate,
+		},
+		{
+			name: "without dates falls back to the creation time",
+			article: func() internal.ArticleNews {
+				article := mockArticle()
+				article.LastUpdateDate = ""
+				article.PublishDate = time.Time{}
+				article.CreateAt = createAt
+				return article
+			},
+			want: createAt,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, articleUpdated(tt.article()))
+		})
+	}
+}
+
+func Test_toAtom_withoutDates(t *testing.T) {
+	article := mockArticle()
+	article.LastUpdateDate = ""
+	article.PublishDate = time.Time{}
+	article.CreateAt = time.Time{}
+	updated := time.Date(2022, 6, 15, 8, 0, 21, 0, time.UTC)
+
+	f := feed{articles: []internal.ArticleNews{article}, updated: updated}
+	doc := toAtom(f)
+
+	require.Len(t, doc.Entries, 1)
+	assert.Equal(t, "2022-06-15T08:00:21Z", doc.Entries[0].Updated)
+}
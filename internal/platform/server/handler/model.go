@@ -2,28 +2,150 @@ package handler
 
 import (
 	"time"
+
+	"github.com/patriciabonaldy/sports-news/internal"
 )
 
 // swagger:model RequestID
 type RequestID struct {
-	ID string `uri:"id" binding:"required" example:"8001122"`
+	ID string `uri:"id" binding:"required" example:"brentford-8001122"`
+}
+
+// swagger:model RequestDeadLetter
+type RequestDeadLetter struct {
+	Provider string `uri:"provider" binding:"required" example:"brentford"`
+	ID       string `uri:"id" binding:"required" example:"8001122"`
+}
+
+// swagger:model RequestFormat
+type RequestFormat struct {
+	Format string `form:"format" example:"markdown"`
+}
+
+// swagger:model RequestArticles
+type RequestArticles struct {
+	Limit  int    `form:"limit" example:"20"`
+	Cursor string `form:"cursor"`
+	Sort   string `form:"sort" example:"-publish_date"`
+
+	Club      string `form:"club" example:"Brentford"`
+	Taxonomy  string `form:"taxonomy" example:"History"`
+	Published string `form:"published" example:"true"`
+	From      string `form:"from" example:"2022-06-01"`
+	To        string `form:"to" example:"2022-06-30"`
+
+	IncludeHidden   bool `form:"include_hidden" example:"true"`
+	IncludeArchived bool `form:"include_archived" example:"true"`
+}
+
+// swagger:model ResponseArticles
+type ResponseArticles struct {
+	Data       []Response `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// swagger:model Pagination
+type Pagination struct {
+	Limit int    `json:"limit"`
+	Sort  string `json:"sort"`
+	Next  string `json:"next,omitempty"`
+}
+
+// swagger:model RequestSearch
+type RequestSearch struct {
+	Q     string `form:"q" binding:"required" example:"Toney"`
+	Limit int    `form:"limit" example:"20"`
+}
+
+// swagger:model ResponseSearch
+type ResponseSearch struct {
+	Data []SearchHit `json:"data"`
+}
+
+// swagger:model SearchHit
+type SearchHit struct {
+	Response
+	Score      float64  `json:"score"`
+	Highlights []string `json:"highlights"`
+}
+
+// swagger:model RequestRevision
+type RequestRevision struct {
+	ID      string `uri:"id" binding:"required" example:"brentford-8001122"`
+	Rev     int    `uri:"rev" binding:"required,min=1" example:"1"`
+	Compare int    `form:"compare" binding:"min=0" example:"2"`
+}
+
+// swagger:model ResponseRevisions
+type ResponseRevisions struct {
+	Data    []ResponseRevision `json:"data"`
+	Current int                `json:"current"`
+}
+
+// swagger:model ResponseRevision
+type ResponseRevision struct {
+	Revision   int        `json:"revision"`
+	ReplacedAt *time.Time `json:"replaced_at,omitempty"`
+	Article    Response   `json:"article"`
+}
+
+// swagger:model ResponseRevisionDiff
+type ResponseRevisionDiff struct {
+	ResponseRevision
+	ComparedTo int      `json:"compared_to,omitempty"`
+	Changes    []Change `json:"changes"`
+}
+
+// swagger:model Change
+type Change struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// swagger:model ResponseDeadLetters
+type ResponseDeadLetters struct {
+	Data []ResponseDeadLetter `json:"data"`
+}
+
+// swagger:model ResponseDeadLetter
+type ResponseDeadLetter struct {
+	ArticleID   string    `json:"article_id"`
+	Provider    string    `json:"provider"`
+	ArticleURL  string    `json:"article_url"`
+	IsPublished bool      `json:"is_published"`
+	Stage       string    `json:"stage"`
+	Error       string    `json:"error"`
+	Attempts    int       `json:"attempts"`
+	FailedAt    time.Time `json:"failed_at"`
 }
 
 // swagger:model Response
 type Response struct {
-	NewsID            string    `json:"news_id"`
-	ClubName          string    `json:"club_name"`
-	ClubWebsiteURL    string    `json:"club_website_url"`
-	ArticleURL        string    `json:"article_url"`
-	Title             string    `json:"title"`
-	Subtitle          string    `json:"subtitle"`
-	BodyText          string    `json:"body_text"`
-	GalleryImageURLs  string    `json:"gallery_image_urls"`
-	VideoURL          string    `json:"video_url"`
-	Taxonomies        string    `json:"taxonomies"`
-	TeaserText        string    `json:"teaser_text"`
-	ThumbnailImageURL string    `json:"thumbnail_image_url"`
-	PublishDate       string    `json:"publish_date"`
-	IsPublished       bool      `json:"is_published"`
-	CreateAt          time.Time `json:"create_at"`
+	NewsID            string         `json:"news_id"`
+	ClubName          string         `json:"club_name"`
+	ClubWebsiteURL    string         `json:"club_website_url"`
+	ArticleURL        string         `json:"article_url"`
+	Title             string         `json:"title"`
+	Subtitle          string         `json:"subtitle"`
+	BodyText          string         `json:"body_text"`
+	Body              *internal.Body `json:"body,omitempty"`
+	GalleryImageURLs  []string       `json:"gallery_image_urls"`
+	VideoURL          string         `json:"video_url"`
+	Taxonomies        []string       `json:"taxonomies"`
+	TeaserText        string         `json:"teaser_text"`
+	ThumbnailImageURL string         `json:"thumbnail_image_url"`
+	PublishDate       *time.Time     `json:"publish_date,omitempty"`
+	LastUpdateDate    string         `json:"last_update_date"`
+	IsPublished       bool           `json:"is_published"`
+	CreateAt          time.Time      `json:"create_at"`
+	DeletedAt         *time.Time     `json:"deleted_at,omitempty"`
+	ArchivedAt        *time.Time     `json:"archived_at,omitempty"`
+}
+
+// swagger:model RequestFeed
+type RequestFeed struct {
+	Limit    int    `form:"limit" example:"20"`
+	Club     string `form:"club" example:"Brentford"`
+	Taxonomy string `form:"taxonomy" example:"History"`
 }
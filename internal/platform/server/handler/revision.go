@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/business"
+)
+
+func (a *ArticleHandler) GetRevisions() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req RequestID
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"msg": err.Error()})
+			return
+		}
+
+		ans, err := a.service.GetRevisions(ctx, req.ID, isAdmin(ctx))
+		if err != nil {
+			revisionError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponseRevisions(ans))
+	}
+}
+
+func (a *ArticleHandler) GetRevision() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req RequestRevision
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"msg": err.Error()})
+			return
+		}
+
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"msg": err.Error()})
+			return
+		}
+
+		ans, err := a.service.GetRevision(ctx, req.ID, req.Rev, req.Compare, isAdmin(ctx))
+		if err != nil {
+			revisionError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, toResponseRevisionDiff(ans))
+	}
+}
+
+func revisionError(ctx *gin.Context, err error) {
+	switch err {
+	case internal.ErrIDIsEmpty,
+		internal.ErrArticleNotFound,
+		internal.ErrRevisionNotFound:
+		ctx.JSON(http.StatusBadRequest, err.Error())
+
+	default:
+		ctx.JSON(http.StatusInternalServerError, err.Error())
+	}
+}
+
+func toResponseRevisions(revisions []internal.Revision) ResponseRevisions {
+	resp := ResponseRevisions{
+		Data:    make([]ResponseRevision, 0, len(revisions)),
+		Current: len(revisions) + 1,
+	}
+	for _, r := range revisions {
+		resp.Data = append(resp.Data, toResponseRevision(r))
+	}
+
+	return resp
+}
+
+func toResponseRevision(revision internal.Revision) ResponseRevision {
+	resp := ResponseRevision{
+		Revision: revision.Number,
+		Article:  toResponse(&revision.Article),
+	}
+	if !revision.ReplacedAt.IsZero() {
+		replacedAt := revision.ReplacedAt
+		resp.ReplacedAt = &replacedAt
+	}
+
+	return resp
+}
+
+func toResponseRevisionDiff(diff *business.RevisionDiff) ResponseRevisionDiff {
+	resp := ResponseRevisionDiff{
+		ResponseRevision: toResponseRevision(diff.Revision),
+		ComparedTo:       diff.ComparedTo,
+		Changes:          make([]Change, 0, len(diff.Changes)),
+	}
+	for _, c := range diff.Changes {
+		resp.Changes = append(resp.Changes, Change{Field: c.Field, From: c.From, To: c.To})
+	}
+
+	return resp
+}
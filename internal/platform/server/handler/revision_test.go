@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/sports-news/internal/business"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/memory"
+)
+
+func TestHandler_Revisions(t *testing.T) {
+	repository := memory.NewStorage()
+	article := mockArticle()
+	_, err := repository.Upsert(context.Background(), article)
+	require.NoError(t, err)
+
+	article.Title = "Pontus explains how fatherhood has calmed him down"
+	_, err = repository.Upsert(context.Background(), article)
+	require.NoError(t, err)
+
+	log := logger.New()
+	svc := business.NewService(repository, log)
+	handler := New(svc, log)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/articles/:id/revisions", handler.GetRevisions())
+	r.GET("/articles/:id/revisions/:rev", handler.GetRevision())
+
+	t.Run("given an unknown article it returns 400", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/articles/1/revisions", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("given an article it returns its revisions", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/articles/641838/revisions", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		var resp ResponseRevisions
+		err = json.NewDecoder(res.Body).Decode(&resp)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.Len(t, resp.Data, 1)
+		assert.Equal(t, 1, resp.Data[0].Revision)
+		assert.Equal(t, "Pontus explains", resp.Data[0].Article.Title)
+		assert.Equal(t, 2, resp.Current)
+	})
+
+	t.Run("given an unknown revision it returns 400", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/articles/641838/revisions/3", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("given a revision it returns the diff with the next version", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/articles/641838/revisions/1", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		var resp ResponseRevisionDiff
+		err = json.NewDecoder(res.Body).Decode(&resp)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, 2, resp.ComparedTo)
+		assert.Equal(t, []Change{{
+			Field: "title",
+			From:  "Pontus explains",
+			To:    "Pontus explains how fatherhood has calmed him down",
+		}}, resp.Changes)
+	})
+}
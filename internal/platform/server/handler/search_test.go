@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/sports-news/internal/business"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/memory"
+)
+
+func TestHandler_SearchArticles(t *testing.T) {
+	repository := memory.NewStorage()
+	toney := mockArticle()
+	toney.NewsID = "641900"
+	toney.Title = "Toney called up by England"
+	toney.BodyText = "Ivan Toney has been named in the England squad."
+	require.NoError(t, repository.Save(context.Background(), toney))
+	require.NoError(t, repository.Save(context.Background(), mockArticle()))
+
+	log := logger.New()
+	svc := business.NewService(repository, log)
+	handler := New(svc, log)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/articles/search", handler.SearchArticles())
+	r.GET("/articles/:id", handler.GetArticleByID())
+
+	t.Run("given a request without text it returns 400", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/articles/search?q=", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("given a valid request it returns 200", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/articles/search?q=toney", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		var resp ResponseSearch
+		err = json.NewDecoder(res.Body).Decode(&resp)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.Len(t, resp.Data, 1)
+		assert.Equal(t, "641900", resp.Data[0].NewsID)
+		assert.Equal(t, float64(11), resp.Data[0].Score)
+		assert.Equal(t, []string{
+			"<em>Toney</em> called up by England",
+			"Ivan <em>Toney</em> has been named in the England squad.",
+		}, resp.Data[0].Highlights)
+	})
+}
@@ -5,9 +5,6 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"os"
-	"os/signal"
-	"time"
 
 	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
 
@@ -17,24 +14,29 @@ import (
 )
 
 type Server struct {
-	httpAddr string
-	engine   *gin.Engine
-	handler  handler.ArticleHandler
-
-	shutdownTimeout time.Duration
+	httpAddr    string
+	engine      *gin.Engine
+	http        *http.Server
+	handler     handler.ArticleHandler
+	deadLetters handler.DeadLetterHandler
+	adminToken  string
 }
 
-func New(ctx context.Context, config *config.Config, handler handler.ArticleHandler) (context.Context, Server) {
+func New(config *config.Config, handler handler.ArticleHandler, deadLetters handler.DeadLetterHandler) Server {
 	srv := Server{
-		engine:   gin.New(),
-		httpAddr: fmt.Sprintf("%s:%d", config.Host, config.Port),
-		handler:  handler,
-
-		shutdownTimeout: time.Duration(config.ShutdownTimeout) + time.Second,
+		engine:      gin.New(),
+		httpAddr:    fmt.Sprintf("%s:%d", config.Host, config.Port),
+		handler:     handler,
+		deadLetters: deadLetters,
+		adminToken:  config.AdminToken,
+	}
+	srv.http = &http.Server{
+		Addr:    srv.httpAddr,
+		Handler: srv.engine,
 	}
 
 	srv.registerRoutes()
-	return serverContext(ctx), srv
+	return srv
 }
 
 // Middleware is a gin.HandlerFunc that set CORS
@@ -54,44 +56,43 @@ func Middleware() gin.HandlerFunc {
 	}
 }
 func (s *Server) registerRoutes() {
-	s.engine.Use(Middleware())
+	s.engine.Use(Middleware(), handler.Admin(s.adminToken))
 	s.engine.GET("/health", handler.CheckHandler())
 	articles := s.engine.Group("/articles")
 	{
 		articles.GET("", s.handler.GetArticles())
+		articles.GET("/search", s.handler.SearchArticles())
 		articles.GET("/:id", s.handler.GetArticleByID())
+		articles.GET("/:id/revisions", s.handler.GetRevisions())
+		articles.GET("/:id/revisions/:rev", s.handler.GetRevision())
+	}
+	feeds := s.engine.Group("/feeds")
+	{
+		feeds.GET("/rss", s.handler.FeedRSS())
+		feeds.GET("/atom", s.handler.FeedAtom())
+		feeds.GET("/json", s.handler.FeedJSON())
+	}
+	deadLetters := s.engine.Group("/admin/dead-letters", handler.RequireAdmin())
+	{
+		deadLetters.GET("", s.deadLetters.GetDeadLetters())
+		deadLetters.POST("/:provider/:id/retry", s.deadLetters.RetryDeadLetter())
+		deadLetters.DELETE("/:provider/:id", s.deadLetters.DropDeadLetter())
 	}
 }
 
-func (s *Server) Run(ctx context.Context) error {
+// Start serves the API in the background.
+func (s *Server) Start() {
 	log.Println("Server running on", s.httpAddr)
 
-	srv := &http.Server{
-		Addr:    s.httpAddr,
-		Handler: s.engine,
-	}
-
 	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := s.http.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatal("server shut down", err)
 		}
 	}()
-
-	<-ctx.Done()
-	ctxShutDown, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
-	defer cancel()
-
-	return srv.Shutdown(ctxShutDown)
 }
 
-func serverContext(ctx context.Context) context.Context {
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-	ctx, cancel := context.WithCancel(ctx)
-	go func() {
-		<-c
-		cancel()
-	}()
-
-	return ctx
+// Shutdown stops accepting requests and waits for the ones in progress
+// until the context is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.http.Shutdown(ctx)
 }
@@ -0,0 +1,168 @@
+package bolt
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+)
+
+// ArticleNews is a structure of article to be stored
+type ArticleNews struct {
+	ArticleID         string         `json:"article_id"`
+	Provider          string         `json:"provider,omitempty"`
+	ClubName          string         `json:"club_name"`
+	ClubWebsiteURL    string         `json:"club_website_url"`
+	ArticleURL        string         `json:"article_url,omitempty"`
+	Title             string         `json:"title"`
+	Subtitle          string         `json:"subtitle,omitempty"`
+	BodyText          string         `json:"body_text,omitempty"`
+	Body              *internal.Body `json:"body,omitempty"`
+	GalleryImageURLs  []string       `json:"gallery_image_urls,omitempty"`
+	VideoURL          string         `json:"video_url,omitempty"`
+	Taxonomies        []string       `json:"taxonomies,omitempty"`
+	TeaserText        string         `json:"teaser_text,omitempty"`
+	ThumbnailImageURL string         `json:"thumbnail_image_url,omitempty"`
+	PublishDate       time.Time      `json:"publish_date"`
+	LastUpdateDate    string         `json:"last_update_date,omitempty"`
+	IsPublished       bool           `json:"is_published,omitempty"`
+	CreateAt          time.Time      `json:"create_at"`
+	DeletedAt         *time.Time     `json:"deleted_at,omitempty"`
+	ArchivedAt        *time.Time     `json:"archived_at,omitempty"`
+}
+
+// Revision is a previous version of an article.
+type Revision struct {
+	Number     int         `json:"revision"`
+	Article    ArticleNews `json:"article"`
+	ReplacedAt time.Time   `json:"replaced_at"`
+}
+
+// DeadLetter is an item of a provider that could not be stored.
+type DeadLetter struct {
+	ArticleID   string    `json:"article_id"`
+	Provider    string    `json:"provider"`
+	ArticleURL  string    `json:"article_url"`
+	IsPublished bool      `json:"is_published,omitempty"`
+	Stage       string    `json:"stage"`
+	Error       string    `json:"error"`
+	Attempts    int       `json:"attempts"`
+	FailedAt    time.Time `json:"failed_at"`
+}
+
+func decodeArticle(data []byte) (internal.ArticleNews, error) {
+	var article ArticleNews
+	if err := json.Unmarshal(data, &article); err != nil {
+		return internal.ArticleNews{}, err
+	}
+
+	return parseToBusinessArticleNews(article), nil
+}
+
+func encodeArticle(article internal.ArticleNews) ([]byte, error) {
+	return json.Marshal(parseToArticleNewsDB(article))
+}
+
+func parseToBusinessArticleNews(result ArticleNews) internal.ArticleNews {
+	article := internal.ArticleNews{
+		NewsID:            result.ArticleID,
+		Provider:          result.Provider,
+		ClubName:          result.ClubName,
+		ClubWebsiteURL:    result.ClubWebsiteURL,
+		ArticleURL:        result.ArticleURL,
+		Title:             result.Title,
+		Subtitle:          result.Subtitle,
+		BodyText:          result.BodyText,
+		GalleryImageURLs:  result.GalleryImageURLs,
+		VideoURL:          result.VideoURL,
+		Taxonomies:        result.Taxonomies,
+		TeaserText:        result.TeaserText,
+		ThumbnailImageURL: result.ThumbnailImageURL,
+		PublishDate:       result.PublishDate.UTC(),
+		LastUpdateDate:    result.LastUpdateDate,
+		IsPublished:       result.IsPublished,
+		CreateAt:          result.CreateAt.UTC(),
+	}
+	if result.Body != nil {
+		article.Body = *result.Body
+	}
+
+	if result.DeletedAt != nil {
+		article.DeletedAt = result.DeletedAt.UTC()
+	}
+
+	if result.ArchivedAt != nil {
+		article.ArchivedAt = result.ArchivedAt.UTC()
+	}
+
+	return article
+}
+
+func parseToArticleNewsDB(article internal.ArticleNews) ArticleNews {
+	a := ArticleNews{
+		ArticleID:         article.NewsID,
+		Provider:          article.Provider,
+		ClubName:          article.ClubName,
+		ClubWebsiteURL:    article.ClubWebsiteURL,
+		ArticleURL:        article.ArticleURL,
+		Title:             article.Title,
+		Subtitle:          article.Subtitle,
+		BodyText:          article.BodyText,
+		GalleryImageURLs:  article.GalleryImageURLs,
+		VideoURL:          article.VideoURL,
+		Taxonomies:        article.Taxonomies,
+		TeaserText:        article.TeaserText,
+		ThumbnailImageURL: article.ThumbnailImageURL,
+		PublishDate:       article.PublishDate.UTC(),
+		LastUpdateDate:    article.LastUpdateDate,
+		IsPublished:       article.IsPublished,
+		CreateAt:          article.CreateAt.UTC(),
+	}
+	if !article.Body.IsEmpty() {
+		body := article.Body
+		a.Body = &body
+	}
+
+	if article.IsDeleted() {
+		deletedAt := article.DeletedAt.UTC()
+		a.DeletedAt = &deletedAt
+	}
+
+	if article.IsArchived() {
+		archivedAt := article.ArchivedAt.UTC()
+		a.ArchivedAt = &archivedAt
+	}
+
+	return a
+}
+
+func decodeDeadLetter(data []byte) (internal.DeadLetter, error) {
+	var deadLetter DeadLetter
+	if err := json.Unmarshal(data, &deadLetter); err != nil {
+		return internal.DeadLetter{}, err
+	}
+
+	return internal.DeadLetter{
+		ArticleID:   deadLetter.ArticleID,
+		Provider:    deadLetter.Provider,
+		ArticleURL:  deadLetter.ArticleURL,
+		IsPublished: deadLetter.IsPublished,
+		Stage:       deadLetter.Stage,
+		Error:       deadLetter.Error,
+		Attempts:    deadLetter.Attempts,
+		FailedAt:    deadLetter.FailedAt.UTC(),
+	}, nil
+}
+
+func parseToDeadLetterDB(deadLetter internal.DeadLetter) DeadLetter {
+	return DeadLetter{
+		ArticleID:   deadLetter.ArticleID,
+		Provider:    deadLetter.Provider,
+		ArticleURL:  deadLetter.ArticleURL,
+		IsPublished: deadLetter.IsPublished,
+		Stage:       deadLetter.Stage,
+		Error:       deadLetter.Error,
+		Attempts:    deadLetter.Attempts,
+		FailedAt:    deadLetter.FailedAt.UTC(),
+	}
+}
@@ -0,0 +1,436 @@
+package bolt
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/search"
+)
+
+var (
+	articleBucket = []byte("article")
+	archiveBucket = []byte("article_archive")
+	// revisionBucket holds a bucket per article, keyed by revision number.
+	revisionBucket = []byte("article_revision")
+	deadBucket     = []byte("dead_letter")
+)
+
+// openTimeout bounds the wait for the lock of a file opened by another process.
+const openTimeout = 5 * time.Second
+
+// Repository is a Storage implementation that persists the articles to a
+// local BoltDB file, for deployments without a database server. Queries
+// read every article, it is meant for small collections.
+type Repository struct {
+	db  *bolt.DB
+	log logger.Logger
+}
+
+var _ internal.Storage = &Repository{}
+
+// NewDBStorage opens, or creates, the BoltDB file of the configuration.
+func NewDBStorage(cfg *config.Bolt, log logger.Logger) (*Repository, error) {
+	db, err := bolt.Open(cfg.Path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{articleBucket, archiveBucket, revisionBucket, deadBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Repository{db: db, log: log}, nil
+}
+
+// Close releases the file.
+func (r *Repository) Close() error {
+	return r.db.Close()
+}
+
+// GetArticles returns every stored article, an empty slice when there
+// are none.
+func (r *Repository) GetArticles(_ context.Context) ([]internal.ArticleNews, error) {
+	results := make([]internal.ArticleNews, 0)
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		results, err = readArticles(tx.Bucket(articleBucket), results)
+		return err
+	})
+
+	return results, err
+}
+
+// GetArticlesPage returns a page of articles with the same ordering
+// and cursors as the mongo implementation.
+func (r *Repository) GetArticlesPage(_ context.Context, query internal.ArticleQuery) (*internal.ArticlePage, error) {
+	var articles []internal.ArticleNews
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		if articles, err = readArticles(tx.Bucket(articleBucket), articles); err != nil {
+			return err
+		}
+
+		if query.IncludeArchived {
+			articles, err = readArticles(tx.Bucket(archiveBucket), articles)
+		}
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return internal.Paginate(articles, query)
+}
+
+// Search scores the articles with the same weights as the mongo text index.
+func (r *Repository) Search(ctx context.Context, query internal.SearchQuery) ([]internal.SearchResult, error) {
+	articles, err := r.GetArticles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return search.Rank(search.Visible(articles), query)
+}
+
+func (r *Repository) GetArticleByID(_ context.Context, articleID string) (*internal.ArticleNews, error) {
+	var article internal.ArticleNews
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		article, err = getArticle(tx.Bucket(articleBucket), articleID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &article, nil
+}
+
+func (r *Repository) Save(_ context.Context, article internal.ArticleNews) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return putArticle(tx.Bucket(articleBucket), article)
+	})
+}
+
+// Upsert inserts the article or replaces the stored one when it changed.
+// The original CreateAt is kept, archived articles are updated in the
+// archive. Bolt runs a single writer at a time, so concurrent upserts are
+// serialised.
+func (r *Repository) Upsert(_ context.Context, article internal.ArticleNews) (internal.UpsertResult, error) {
+	result := internal.UpsertUnchanged
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		articles := tx.Bucket(articleBucket)
+		stored, err := getArticle(articles, article.NewsID)
+		if err == internal.ErrArticleNotFound {
+			// an archived article that is still listed stays in the archive
+			articles = tx.Bucket(archiveBucket)
+			stored, err = getArticle(articles, article.NewsID)
+			article.ArchivedAt = stored.ArchivedAt
+		}
+
+		if err == internal.ErrArticleNotFound {
+			result = internal.UpsertInserted
+			return putArticle(tx.Bucket(articleBucket), article)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !article.ChangedFrom(stored) {
+			if article.FillsBody(stored) {
+				article.CreateAt = stored.CreateAt
+				return putArticle(articles, article)
+			}
+
+			if stored.Provider == article.Provider {
+				return nil
+			}
+
+			stored.Provider = article.Provider
+			return putArticle(articles, stored)
+		}
+
+		if err = putRevision(tx.Bucket(revisionBucket), stored); err != nil {
+			return err
+		}
+
+		result = internal.UpsertUpdated
+		article.CreateAt = stored.CreateAt
+
+		return putArticle(articles, article)
+	})
+	if err != nil {
+		return internal.UpsertUnchanged, err
+	}
+
+	return result, nil
+}
+
+// Withdraw marks deleted the articles missing from the list of the
+// provider and unpublished the ones it flags.
+func (r *Repository) Withdraw(_ context.Context, withdrawal internal.Withdrawal) (internal.WithdrawResult, error) {
+	var result internal.WithdrawResult
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(articleBucket)
+		articles, err := readArticles(bucket, nil)
+		if err != nil {
+			return err
+		}
+
+		for _, article := range articles {
+			switch {
+			case !withdrawal.Covers(article):
+				continue
+			case !withdrawal.IsListed(article.NewsID):
+				if article.IsDeleted() {
+					continue
+				}
+
+				article.DeletedAt = withdrawal.At
+				result.Deleted++
+			case withdrawal.IsUnpublished(article.NewsID):
+				if !article.IsPublished {
+					continue
+				}
+
+				article.IsPublished = false
+				result.Unpublished++
+			default:
+				continue
+			}
+
+			if err = putArticle(bucket, article); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return internal.WithdrawResult{}, err
+	}
+
+	return result, nil
+}
+
+// Archive moves the articles selected by the query to the archive bucket.
+func (r *Repository) Archive(_ context.Context, query internal.ArchiveQuery) ([]internal.ArticleNews, error) {
+	var archived []internal.ArticleNews
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(articleBucket)
+		articles, err := readArticles(bucket, nil)
+		if err != nil {
+			return err
+		}
+
+		for _, article := range articles {
+			if !query.Matches(article) {
+				continue
+			}
+
+			article.ArchivedAt = query.At
+			if err = putArticle(tx.Bucket(archiveBucket), article); err != nil {
+				return err
+			}
+
+			if err = bucket.Delete([]byte(article.NewsID)); err != nil {
+				return err
+			}
+
+			archived = append(archived, article)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return archived, nil
+}
+
+// GetRevisions returns the previous versions of the article, oldest first.
+func (r *Repository) GetRevisions(_ context.Context, articleID string) ([]internal.Revision, error) {
+	revisions := make([]internal.Revision, 0)
+	err := r.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(revisionBucket).Bucket([]byte(articleID))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(_, data []byte) error {
+			var revision Revision
+			if err := json.Unmarshal(data, &revision); err != nil {
+				return err
+			}
+
+			revisions = append(revisions, internal.Revision{
+				Number:     revision.Number,
+				Article:    parseToBusinessArticleNews(revision.Article),
+				ReplacedAt: revision.ReplacedAt.UTC(),
+			})
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// SaveDeadLetter stores the dead letter, replacing the one of the same
+// article of the provider.
+func (r *Repository) SaveDeadLetter(_ context.Context, deadLetter internal.DeadLetter) error {
+	data, err := json.Marshal(parseToDeadLetterDB(deadLetter))
+	if err != nil {
+		return err
+	}
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(deadBucket).Put(deadLetterKey(deadLetter.Provider, deadLetter.ArticleID), data)
+	})
+}
+
+// GetDeadLetters returns the dead letters, oldest first.
+func (r *Repository) GetDeadLetters(_ context.Context) ([]internal.DeadLetter, error) {
+	deadLetters := make([]internal.DeadLetter, 0)
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(deadBucket).ForEach(func(_, data []byte) error {
+			deadLetter, err := decodeDeadLetter(data)
+			if err != nil {
+				return err
+			}
+
+			deadLetters = append(deadLetters, deadLetter)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	internal.SortDeadLetters(deadLetters)
+
+	return deadLetters, nil
+}
+
+func (r *Repository) GetDeadLetter(_ context.Context, provider, articleID string) (*internal.DeadLetter, error) {
+	var deadLetter internal.DeadLetter
+	err := r.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(deadBucket).Get(deadLetterKey(provider, articleID))
+		if data == nil {
+			return internal.ErrDeadLetterNotFound
+		}
+
+		var err error
+		deadLetter, err = decodeDeadLetter(data)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &deadLetter, nil
+}
+
+func (r *Repository) DeleteDeadLetter(_ context.Context, provider, articleID string) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(deadBucket)
+		key := deadLetterKey(provider, articleID)
+		if bucket.Get(key) == nil {
+			return internal.ErrDeadLetterNotFound
+		}
+
+		return bucket.Delete(key)
+	})
+}
+
+// deadLetterKey is the key of the dead letter of an article of a
+// provider, provider names never contain a NUL.
+func deadLetterKey(provider, articleID string) []byte {
+	return []byte(provider + "\x00" + articleID)
+}
+
+func getArticle(bucket *bolt.Bucket, articleID string) (internal.ArticleNews, error) {
+	data := bucket.Get([]byte(articleID))
+	if data == nil {
+		return internal.ArticleNews{}, internal.ErrArticleNotFound
+	}
+
+	return decodeArticle(data)
+}
+
+func putArticle(bucket *bolt.Bucket, article internal.ArticleNews) error {
+	data, err := encodeArticle(article)
+	if err != nil {
+		return err
+	}
+
+	return bucket.Put([]byte(article.NewsID), data)
+}
+
+// putRevision stores the replaced version with the next revision number
+// of the article.
+func putRevision(revisions *bolt.Bucket, stored internal.ArticleNews) error {
+	bucket, err := revisions.CreateBucketIfNotExists([]byte(stored.NewsID))
+	if err != nil {
+		return err
+	}
+
+	number, err := bucket.NextSequence()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(Revision{
+		Number:     int(number),
+		Article:    parseToArticleNewsDB(stored),
+		ReplacedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// big endian keys keep the revisions in order
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, number)
+
+	return bucket.Put(key, data)
+}
+
+// readArticles appends the articles of the bucket to results, in the
+// order of their IDs.
+func readArticles(bucket *bolt.Bucket, results []internal.ArticleNews) ([]internal.ArticleNews, error) {
+	err := bucket.ForEach(func(_, data []byte) error {
+		article, err := decodeArticle(data)
+		if err != nil {
+			return err
+		}
+
+		results = append(results, article)
+		return nil
+	})
+
+	return results, err
+}
@@ -0,0 +1,53 @@
+package bolt
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/storagetest"
+)
+
+func TestRepository_conformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) internal.Storage {
+		return newRepository(t, filepath.Join(t.TempDir(), "news.db"))
+	})
+}
+
+func TestRepository_reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "news.db")
+	ctx := context.Background()
+	article := storagetest.Article("641838", "2022-06-15 08:00:00")
+
+	repo := newRepository(t, path)
+	require.NoError(t, repo.Save(ctx, article))
+	updated := article
+	updated.Title = "Pontus Jansson explains"
+	_, err := repo.Upsert(ctx, updated)
+	require.NoError(t, err)
+	require.NoError(t, repo.Close())
+
+	repo = newRepository(t, path)
+	got, err := repo.GetArticleByID(ctx, article.NewsID)
+	require.NoError(t, err)
+	assert.Equal(t, updated, *got)
+
+	revisions, err := repo.GetRevisions(ctx, article.NewsID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, article, revisions[0].Article)
+}
+
+func newRepository(t *testing.T, path string) *Repository {
+	repo, err := NewDBStorage(&config.Bolt{Path: path}, logger.New())
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+
+	return repo
+}
@@ -0,0 +1,340 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/search"
+)
+
+// Repository is an in-memory Storage implementation with the semantics
+// of the mongo one, for tests and demos. It is safe for concurrent use,
+// articles are copied in and out so that callers never share their
+// slices with the stored ones.
+type Repository struct {
+	mu        sync.RWMutex
+	articles  []internal.ArticleNews
+	index     map[string]int
+	revisions map[string][]internal.Revision
+	archive   map[string]internal.ArticleNews
+	dead      map[deadLetterKey]internal.DeadLetter
+}
+
+// deadLetterKey identifies the dead letter of an article of a provider.
+type deadLetterKey struct {
+	provider  string
+	articleID string
+}
+
+var _ internal.Storage = &Repository{}
+
+// NewStorage initializes an in-memory implementation of Storage.
+func NewStorage() *Repository {
+	return &Repository{
+		index:     make(map[string]int),
+		revisions: make(map[string][]internal.Revision),
+		archive:   make(map[string]internal.ArticleNews),
+		dead:      make(map[deadLetterKey]internal.DeadLetter),
+	}
+}
+
+// GetArticles returns every stored article, an empty slice when there
+// are none.
+func (r *Repository) GetArticles(_ context.Context) ([]internal.ArticleNews, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return cloneAll(r.articles), nil
+}
+
+// GetArticlesPage returns a page of articles with the same ordering
+// and cursors as the mongo implementation.
+func (r *Repository) GetArticlesPage(_ context.Context, query internal.ArticleQuery) (*internal.ArticlePage, error) {
+	r.mu.RLock()
+	articles := cloneAll(r.articles)
+	if query.IncludeArchived {
+		for _, article := range r.archive {
+			articles = append(articles, clone(article))
+		}
+	}
+	r.mu.RUnlock()
+
+	return internal.Paginate(articles, query)
+}
+
+// Search scores the articles with the same weights as the mongo text index.
+func (r *Repository) Search(_ context.Context, query internal.SearchQuery) ([]internal.SearchResult, error) {
+	r.mu.RLock()
+	articles := cloneAll(r.articles)
+	r.mu.RUnlock()
+
+	return search.Rank(search.Visible(articles), query)
+}
+
+func (r *Repository) GetArticleByID(_ context.Context, articleID string) (*internal.ArticleNews, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.index[articleID]
+	if !ok {
+		return nil, internal.ErrArticleNotFound
+	}
+
+	article := clone(r.articles[i])
+	return &article, nil
+}
+
+func (r *Repository) Save(_ context.Context, article internal.ArticleNews) error {
+	article = clone(article)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i, ok := r.index[article.NewsID]; ok {
+		r.articles[i] = article
+		return nil
+	}
+
+	r.index[article.NewsID] = len(r.articles)
+	r.articles = append(r.articles, article)
+
+	return nil
+}
+
+// Upsert inserts the article or replaces the stored one when it changed.
+// The original CreateAt is kept, archived articles are updated in the
+// archive.
+func (r *Repository) Upsert(_ context.Context, article internal.ArticleNews) (internal.UpsertResult, error) {
+	article = clone(article)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.index[article.NewsID]
+	if !ok {
+		if archived, ok := r.archive[article.NewsID]; ok {
+			return r.upsertArchived(article, archived), nil
+		}
+
+		r.index[article.NewsID] = len(r.articles)
+		r.articles = append(r.articles, article)
+
+		return internal.UpsertInserted, nil
+	}
+
+	if !article.ChangedFrom(r.articles[i]) {
+		if article.FillsBody(r.articles[i]) {
+			article.CreateAt = r.articles[i].CreateAt
+			r.articles[i] = article
+		}
+
+		r.articles[i].Provider = article.Provider
+		return internal.UpsertUnchanged, nil
+	}
+
+	r.revisions[article.NewsID] = append(r.revisions[article.NewsID], internal.Revision{
+		Number:     len(r.revisions[article.NewsID]) + 1,
+		Article:    r.articles[i],
+		ReplacedAt: time.Now().UTC(),
+	})
+
+	article.CreateAt = r.articles[i].CreateAt
+	r.articles[i] = article
+
+	return internal.UpsertUpdated, nil
+}
+
+// upsertArchived replaces the archived copy of an article that is still
+// listed, it stays in the archive.
+func (r *Repository) upsertArchived(article, archived internal.ArticleNews) internal.UpsertResult {
+	article.CreateAt = archived.CreateAt
+	article.ArchivedAt = archived.ArchivedAt
+	if !article.ChangedFrom(archived) {
+		if article.FillsBody(archived) {
+			r.archive[article.NewsID] = article
+		}
+
+		return internal.UpsertUnchanged
+	}
+
+	r.revisions[article.NewsID] = append(r.revisions[article.NewsID], internal.Revision{
+		Number:     len(r.revisions[article.NewsID]) + 1,
+		Article:    archived,
+		ReplacedAt: time.Now().UTC(),
+	})
+	r.archive[article.NewsID] = article
+
+	return internal.UpsertUpdated
+}
+
+// Withdraw marks deleted the articles missing from the list of the
+// provider and unpublished the ones it flags.
+func (r *Repository) Withdraw(_ context.Context, withdrawal internal.Withdrawal) (internal.WithdrawResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result internal.WithdrawResult
+	for i, article := range r.articles {
+		switch {
+		case !withdrawal.Covers(article):
+		case !withdrawal.IsListed(article.NewsID):
+			if !article.IsDeleted() {
+				r.articles[i].DeletedAt = withdrawal.At
+				result.Deleted++
+			}
+		case withdrawal.IsUnpublished(article.NewsID):
+			if article.IsPublished {
+				r.articles[i].IsPublished = false
+				result.Unpublished++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Archive moves the articles selected by the query to the archive.
+func (r *Repository) Archive(_ context.Context, query internal.ArchiveQuery) ([]internal.ArticleNews, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var archived []internal.ArticleNews
+	kept := r.articles[:0]
+	for _, article := range r.articles {
+		if !query.Matches(article) {
+			kept = append(kept, article)
+			continue
+		}
+
+		article.ArchivedAt = query.At
+		r.archive[article.NewsID] = article
+		archived = append(archived, clone(article))
+	}
+
+	r.articles = kept
+	for i, article := range r.articles {
+		r.index[article.NewsID] = i
+	}
+
+	for _, article := range archived {
+		delete(r.index, article.NewsID)
+	}
+
+	return archived, nil
+}
+
+// GetRevisions returns the previous versions of the article, oldest first.
+func (r *Repository) GetRevisions(_ context.Context, articleID string) ([]internal.Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revisions := make([]internal.Revision, 0, len(r.revisions[articleID]))
+	for _, revision := range r.revisions[articleID] {
+		revision.Article = clone(revision.Article)
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
+
+// SaveDeadLetter stores the dead letter, replacing the one of the same
+// article of the provider.
+func (r *Repository) SaveDeadLetter(_ context.Context, deadLetter internal.DeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dead[deadLetterKey{provider: deadLetter.Provider, articleID: deadLetter.ArticleID}] = deadLetter
+
+	return nil
+}
+
+// GetDeadLetters returns the dead letters, oldest first.
+func (r *Repository) GetDeadLetters(_ context.Context) ([]internal.DeadLetter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deadLetters := make([]internal.DeadLetter, 0, len(r.dead))
+	for _, deadLetter := range r.dead {
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	internal.SortDeadLetters(deadLetters)
+
+	return deadLetters, nil
+}
+
+func (r *Repository) GetDeadLetter(_ context.Context, provider, articleID string) (*internal.DeadLetter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deadLetter, ok := r.dead[deadLetterKey{provider: provider, articleID: articleID}]
+	if !ok {
+		return nil, internal.ErrDeadLetterNotFound
+	}
+
+	return &deadLetter, nil
+}
+
+func (r *Repository) DeleteDeadLetter(_ context.Context, provider, articleID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := deadLetterKey{provider: provider, articleID: articleID}
+	if _, ok := r.dead[key]; !ok {
+		return internal.ErrDeadLetterNotFound
+	}
+
+	delete(r.dead, key)
+
+	return nil
+}
+
+func cloneAll(articles []internal.ArticleNews) []internal.ArticleNews {
+	clones := make([]internal.ArticleNews, 0, len(articles))
+	for _, article := range articles {
+		clones = append(clones, clone(article))
+	}
+
+	return clones
+}
+
+// clone returns a copy of the article that shares no slices with it.
+func clone(article internal.ArticleNews) internal.ArticleNews {
+	article.GalleryImageURLs = cloneStrings(article.GalleryImageURLs)
+	article.Taxonomies = cloneStrings(article.Taxonomies)
+	if article.Body.Blocks != nil {
+		blocks := make([]internal.Block, len(article.Body.Blocks))
+		for i, block := range article.Body.Blocks {
+			block.Inlines = cloneInlines(block.Inlines)
+			blocks[i] = block
+		}
+
+		article.Body.Blocks = blocks
+	}
+
+	return article
+}
+
+// cloneStrings reads empty lists back as nil, like the other storages
+// that do not store them.
+func cloneStrings(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	return append(make([]string, 0, len(values)), values...)
+}
+
+func cloneInlines(inlines []internal.Inline) []internal.Inline {
+	if inlines == nil {
+		return nil
+	}
+
+	clones := make([]internal.Inline, len(inlines))
+	for i, inline := range inlines {
+		inline.Children = cloneInlines(inline.Children)
+		clones[i] = inline
+	}
+
+	return clones
+}
@@ -0,0 +1,220 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/storagetest"
+)
+
+func TestRepository_conformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) internal.Storage {
+		return NewStorage()
+	})
+}
+
+func TestRepository_Save(t *testing.T) {
+	repo := NewStorage()
+	ctx := context.Background()
+
+	_, err := repo.GetArticleByID(ctx, "641838")
+	assert.Equal(t, internal.ErrArticleNotFound, err)
+
+	article := mockArticle("641838", "2022-06-15 08:00:00")
+	require.NoError(t, repo.Save(ctx, article))
+
+	got, err := repo.GetArticleByID(ctx, article.NewsID)
+	require.NoError(t, err)
+	assert.Equal(t, &article, got)
+}
+
+func TestRepository_copies(t *testing.T) {
+	repo := NewStorage()
+	ctx := context.Background()
+	article := storagetest.Article("641838", "2022-06-15 08:00:00")
+	require.NoError(t, repo.Save(ctx, article))
+
+	// neither the saved nor the returned articles share their slices
+	want := storagetest.Article("641838", "2022-06-15 08:00:00")
+	article.Taxonomies[0] = "History"
+	article.Body.Blocks[0].Inlines[0].Text = "changed"
+	got, err := repo.GetArticleByID(ctx, article.NewsID)
+	require.NoError(t, err)
+	assert.Equal(t, want, *got)
+
+	got.GalleryImageURLs[0] = "https://www.brentfordfc.com/changed.jpg"
+	articles, err := repo.GetArticles(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []internal.ArticleNews{want}, articles)
+}
+
+func TestRepository_GetArticlesPage(t *testing.T) {
+	repo := NewStorage()
+	ctx := context.Background()
+	require.NoError(t, repo.Save(ctx, mockArticle("1", "2022-06-14 08:00:00")))
+	require.NoError(t, repo.Save(ctx, mockArticle("2", "2022-06-15 08:00:00")))
+	require.NoError(t, repo.Save(ctx, mockArticle("3", "2022-06-14 08:00:00")))
+
+	query := internal.ArticleQuery{Limit: 2, Sort: internal.DefaultSort()}
+	got, err := repo.GetArticlesPage(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "3"}, ids(got.Articles))
+	require.NotEmpty(t, got.Next)
+
+	query.Cursor = got.Next
+	got, err = repo.GetArticlesPage(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, ids(got.Articles))
+	assert.Empty(t, got.Next)
+}
+
+func TestRepository_Search(t *testing.T) {
+	repo := NewStorage()
+	ctx := context.Background()
+	title := mockArticle("1", "2022-06-14 08:00:00")
+	title.Title = "Toney called up"
+	body := mockArticle("2", "2022-06-15 08:00:00")
+	body.BodyText = "Toney and Mbeumo"
+	require.NoError(t, repo.Save(ctx, body))
+	require.NoError(t, repo.Save(ctx, title))
+	require.NoError(t, repo.Save(ctx, mockArticle("3", "2022-06-15 08:00:00")))
+
+	got, err := repo.Search(ctx, internal.SearchQuery{Text: "toney", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "1", got[0].Article.NewsID)
+	assert.Equal(t, "2", got[1].Article.NewsID)
+
+	_, err = repo.Search(ctx, internal.SearchQuery{Text: "?"})
+	assert.Equal(t, internal.ErrInvalidSearch, err)
+}
+
+func TestRepository_Upsert(t *testing.T) {
+	repo := NewStorage()
+	ctx := context.Background()
+	article := mockArticle("1", "2022-06-14 08:00:00")
+	article.LastUpdateDate = "2022-06-14 08:00:00"
+	article.CreateAt = time.Now()
+
+	got, err := repo.Upsert(ctx, article)
+	require.NoError(t, err)
+	assert.Equal(t, internal.UpsertInserted, got)
+
+	got, err = repo.Upsert(ctx, article)
+	require.NoError(t, err)
+	assert.Equal(t, internal.UpsertUnchanged, got)
+
+	updated := article
+	updated.Title = "Pontus explains again"
+	updated.CreateAt = time.Now().Add(time.Hour)
+	got, err = repo.Upsert(ctx, updated)
+	require.NoError(t, err)
+	assert.Equal(t, internal.UpsertUpdated, got)
+
+	stored, err := repo.GetArticleByID(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, updated.Title, stored.Title)
+	assert.Equal(t, article.CreateAt, stored.CreateAt)
+}
+
+func TestRepository_Save_parallel(t *testing.T) {
+	repo := NewStorage()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, repo.Save(ctx, mockArticle("1", "2022-06-14 08:00:00")))
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			article := mockArticle("1", "2022-06-14 08:00:00")
+			article.Title = fmt.Sprintf("version %d", i)
+			_, err := repo.Upsert(ctx, article)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	articles, err := repo.GetArticles(ctx)
+	require.NoError(t, err)
+	assert.Len(t, articles, 1)
+}
+
+func TestRepository_Withdraw(t *testing.T) {
+	repo := NewStorage()
+	ctx := context.Background()
+	for _, article := range []internal.ArticleNews{
+		mockArticle("1", "2022-06-01 08:00:00"),
+		mockArticle("2", "2022-06-14 08:00:00"),
+		mockArticle("3", "2022-06-15 08:00:00"),
+		mockArticle("4", "2022-06-16 08:00:00"),
+	} {
+		article.Provider = "brentford"
+		require.NoError(t, repo.Save(ctx, article))
+	}
+	other := mockArticle("5", "2022-06-15 08:00:00")
+	other.Provider = "arsenal"
+	require.NoError(t, repo.Save(ctx, other))
+
+	withdrawal := internal.Withdrawal{
+		Provider:    "brentford",
+		Listed:      []string{"2", "4"},
+		Unpublished: []string{"4"},
+		Since:       time.Date(2022, 6, 14, 8, 0, 0, 0, time.UTC),
+		At:          time.Date(2022, 6, 17, 8, 0, 0, 0, time.UTC),
+	}
+	got, err := repo.Withdraw(ctx, withdrawal)
+	require.NoError(t, err)
+	assert.Equal(t, internal.WithdrawResult{Deleted: 1, Unpublished: 1}, got)
+
+	got, err = repo.Withdraw(ctx, withdrawal)
+	require.NoError(t, err)
+	assert.Equal(t, internal.WithdrawResult{}, got)
+
+	notDeleted := false
+	page, err := repo.GetArticlesPage(ctx, internal.ArticleQuery{
+		Filter: internal.ArticleFilter{Deleted: &notDeleted},
+		Limit:  internal.DefaultPageLimit,
+		Sort:   internal.DefaultSort(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4", "5", "2", "1"}, ids(page.Articles))
+
+	article, err := repo.GetArticleByID(ctx, "3")
+	require.NoError(t, err)
+	assert.Equal(t, withdrawal.At, article.DeletedAt)
+	article, err = repo.GetArticleByID(ctx, "4")
+	require.NoError(t, err)
+	assert.False(t, article.IsPublished)
+}
+
+func ids(articles []internal.ArticleNews) []string {
+	var result []string
+	for _, a := range articles {
+		result = append(result, a.NewsID)
+	}
+
+	return result
+}
+
+func mockArticle(id, publishDate string) internal.ArticleNews {
+	date, _ := internal.ParsePublishDate(publishDate)
+
+	return internal.ArticleNews{
+		NewsID:      id,
+		ClubName:    "Brentford",
+		Title:       "Pontus explains",
+		PublishDate: date,
+		IsPublished: true,
+	}
+}
@@ -0,0 +1,198 @@
+package mongo
+
+import (
+	"context"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/search"
+)
+
+// defaultIndexName is the index mongo creates on _id of every collection.
+const defaultIndexName = "_id_"
+
+// Index is an index the repository needs on one of its collections.
+type Index struct {
+	Collection string
+	Name       string
+	Keys       bson.D
+	Unique     bool
+	Weights    bson.D
+}
+
+func (i Index) model() mongo.IndexModel {
+	opts := options.Index().SetName(i.Name)
+	if i.Unique {
+		opts.SetUnique(true)
+	}
+
+	if len(i.Weights) > 0 {
+		opts.SetWeights(i.Weights)
+	}
+
+	return mongo.IndexModel{Keys: i.Keys, Options: opts}
+}
+
+// IndexState is how a stored index compares to the declared ones.
+type IndexState string
+
+const (
+	IndexOK      IndexState = "ok"
+	IndexMissing IndexState = "missing"
+	IndexChanged IndexState = "changed"
+	IndexExtra   IndexState = "extra"
+)
+
+// IndexStatus is an entry of the index report.
+type IndexStatus struct {
+	Collection string
+	Name       string
+	State      IndexState
+}
+
+// indexes are the indexes declared by the repository.
+func indexes() []Index {
+	return []Index{
+		{Collection: collectionName, Name: "article_id_unique", Keys: bson.D{{Key: "article_id", Value: 1}}, Unique: true},
+		{Collection: collectionName, Name: "club_name", Keys: bson.D{{Key: "club_name", Value: 1}}},
+		{Collection: collectionName, Name: "publish_date", Keys: bson.D{{Key: "publish_date", Value: -1}, {Key: "article_id", Value: -1}}},
+		{Collection: collectionName, Name: "create_at", Keys: bson.D{{Key: "create_at", Value: -1}, {Key: "article_id", Value: -1}}},
+		{Collection: collectionName, Name: "taxonomies", Keys: bson.D{{Key: "taxonomies", Value: 1}}},
+		{Collection: collectionName, Name: "provider_publish_date", Keys: bson.D{{Key: "provider", Value: 1}, {Key: "publish_date", Value: -1}}},
+		textIndex(),
+		{Collection: archiveCollectionName, Name: "article_id_unique", Keys: bson.D{{Key: "article_id", Value: 1}}, Unique: true},
+		{Collection: archiveCollectionName, Name: "publish_date", Keys: bson.D{{Key: "publish_date", Value: -1}, {Key: "article_id", Value: -1}}},
+		{Collection: revisionCollectionName, Name: "article_id_revision", Keys: bson.D{{Key: "article_id", Value: 1}, {Key: "revision", Value: 1}}, Unique: true},
+		{Collection: deadCollectionName, Name: "provider_article_id_unique", Keys: bson.D{{Key: "provider", Value: 1}, {Key: "article_id", Value: 1}}, Unique: true},
+	}
+}
+
+// textIndex is the index of the full-text search, over the fields of
+// search.Fields with their weights.
+func textIndex() Index {
+	index := Index{Collection: collectionName, Name: textIndexName}
+	for _, field := range search.Fields(internal.ArticleNews{}) {
+		index.Keys = append(index.Keys, bson.E{Key: field.Name, Value: "text"})
+		index.Weights = append(index.Weights, bson.E{Key: field.Name, Value: search.Weights[field.Name]})
+	}
+
+	return index
+}
+
+// EnsureIndexes creates the declared indexes that are missing. Existing
+// indexes with the same definition are left as they are.
+func (r *Repository) EnsureIndexes(ctx context.Context) error {
+	byCollection := make(map[string][]mongo.IndexModel)
+	for _, index := range indexes() {
+		byCollection[index.Collection] = append(byCollection[index.Collection], index.model())
+	}
+
+	for collection, models := range byCollection {
+		if _, err := r.getCollection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IndexReport compares the indexes of the database to the declared ones.
+// Declared indexes come first, in order, followed by the extra ones.
+func (r *Repository) IndexReport(ctx context.Context) ([]IndexStatus, error) {
+	declared := indexes()
+	stored := make(map[string]map[string]mongo.IndexSpecification)
+	var collections []string
+	for _, index := range declared {
+		if _, ok := stored[index.Collection]; ok {
+			continue
+		}
+
+		specs, err := r.getCollection(index.Collection).Indexes().ListSpecifications(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		stored[index.Collection] = make(map[string]mongo.IndexSpecification, len(specs))
+		for _, spec := range specs {
+			stored[index.Collection][spec.Name] = *spec
+		}
+
+		collections = append(collections, index.Collection)
+	}
+
+	report := make([]IndexStatus, 0, len(declared))
+	for _, index := range declared {
+		status := IndexStatus{Collection: index.Collection, Name: index.Name, State: IndexOK}
+		spec, ok := stored[index.Collection][index.Name]
+		switch {
+		case !ok:
+			status.State = IndexMissing
+		case !sameIndex(index, spec):
+			status.State = IndexChanged
+		}
+
+		delete(stored[index.Collection], index.Name)
+		report = append(report, status)
+	}
+
+	for _, collection := range collections {
+		names := make([]string, 0, len(stored[collection]))
+		for name := range stored[collection] {
+			if name != defaultIndexName {
+				names = append(names, name)
+			}
+		}
+
+		sort.Strings(names)
+		for _, name := range names {
+			report = append(report, IndexStatus{Collection: collection, Name: name, State: IndexExtra})
+		}
+	}
+
+	return report, nil
+}
+
+// sameIndex compares the keys and uniqueness of an index. Text indexes
+// are stored with internal keys, so only their name is compared.
+func sameIndex(index Index, spec mongo.IndexSpecification) bool {
+	unique := spec.Unique != nil && *spec.Unique
+	if unique != index.Unique {
+		return false
+	}
+
+	if len(index.Weights) > 0 {
+		return true
+	}
+
+	var keys bson.D
+	if err := bson.Unmarshal(spec.KeysDocument, &keys); err != nil || len(keys) != len(index.Keys) {
+		return false
+	}
+
+	for i, key := range keys {
+		if key.Key != index.Keys[i].Key || toInt(key.Value) != toInt(index.Keys[i].Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func toInt(value interface{}) int64 {
+	switch v := value.(type) {
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	default:
+		return 0
+	}
+}
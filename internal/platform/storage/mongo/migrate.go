@@ -0,0 +1,233 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// publishDateFormat is internal.PublishDateLayout for $dateFromString.
+const publishDateFormat = "%Y-%m-%d %H:%M:%S"
+
+// migrations are the changes of the schema, in order. Append new ones,
+// never renumber or edit the ones that were released.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "typed taxonomies, gallery images and publish date",
+		Up:      typedFieldsUp,
+		Down:    typedFieldsDown,
+	},
+	{
+		Version: 2,
+		Name:    "store article url",
+		Up:      articleURLUp,
+		Down:    articleURLDown,
+	},
+	{
+		Version: 3,
+		Name:    "remove duplicated articles",
+		Up:      duplicatedArticlesUp,
+		Down:    noop,
+	},
+	{
+		Version: 4,
+		Name:    "incrowd article ids prefixed with their provider",
+		Up:      providerArticleIDUp,
+		Down:    noop,
+	},
+}
+
+// legacyProvider is the provider of the articles stored before they
+// recorded it, Brentford was the only club then.
+const legacyProvider = "brentford"
+
+// articleCollections are the collections holding articles, with the
+// prefix of the article fields in their documents.
+var articleCollections = map[string]string{
+	collectionName:         "",
+	revisionCollectionName: "article.",
+}
+
+// typedFieldsUp converts the documents stored when taxonomies and
+// gallery_image_urls were comma separated strings and publish_date was
+// a string. Documents that are already converted are not matched.
+func typedFieldsUp(ctx context.Context, db *mongo.Database) error {
+	for collection, prefix := range articleCollections {
+		filter := bson.M{"$or": bson.A{
+			bson.M{prefix + "taxonomies": bson.M{"$type": "string"}},
+			bson.M{prefix + "gallery_image_urls": bson.M{"$type": "string"}},
+			bson.M{prefix + "publish_date": bson.M{"$type": "string"}},
+		}}
+		update := bson.A{
+			bson.M{"$set": bson.M{
+				prefix + "taxonomies":         splitList("$" + prefix + "taxonomies"),
+				prefix + "gallery_image_urls": splitList("$" + prefix + "gallery_image_urls"),
+				prefix + "publish_date":       parseDate("$" + prefix + "publish_date"),
+			}},
+		}
+
+		if _, err := db.Collection(collection).UpdateMany(ctx, filter, update); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// typedFieldsDown converts the typed fields back to strings.
+func typedFieldsDown(ctx context.Context, db *mongo.Database) error {
+	for collection, prefix := range articleCollections {
+		filter := bson.M{"$or": bson.A{
+			bson.M{prefix + "taxonomies": bson.M{"$type": "array"}},
+			bson.M{prefix + "gallery_image_urls": bson.M{"$type": "array"}},
+			bson.M{prefix + "publish_date": bson.M{"$type": "date"}},
+		}}
+		update := bson.A{
+			bson.M{"$set": bson.M{
+				prefix + "taxonomies":         joinList("$" + prefix + "taxonomies"),
+				prefix + "gallery_image_urls": joinList("$" + prefix + "gallery_image_urls"),
+				prefix + "publish_date":       formatDate("$" + prefix + "publish_date"),
+			}},
+		}
+
+		if _, err := db.Collection(collection).UpdateMany(ctx, filter, update); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// articleURLUp drops the content hash of the articles stored before
+// article_url was, so that the next sync stores them again with it.
+func articleURLUp(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(collectionName).UpdateMany(ctx,
+		bson.M{"article_url": bson.M{"$exists": false}},
+		bson.M{"$unset": bson.M{"content_hash": ""}})
+
+	return err
+}
+
+func articleURLDown(ctx context.Context, db *mongo.Database) error {
+	for collection, prefix := range articleCollections {
+		_, err := db.Collection(collection).UpdateMany(ctx,
+			bson.M{prefix + "article_url": bson.M{"$exists": true}},
+			bson.M{"$unset": bson.M{prefix + "article_url": ""}})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// duplicatedArticlesUp keeps the last stored document of every
+// article_id, so that the unique index on it can be created.
+func duplicatedArticlesUp(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection(collectionName)
+	cursor, err := collection.Aggregate(ctx, bson.A{
+		bson.M{"$sort": bson.M{"_id": -1}},
+		bson.M{"$group": bson.M{
+			"_id":   "$article_id",
+			"ids":   bson.M{"$push": "$_id"},
+			"count": bson.M{"$sum": 1},
+		}},
+		bson.M{"$match": bson.M{"count": bson.M{"$gt": 1}}},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var group struct {
+			IDs bson.A `bson:"ids"`
+		}
+		if err = cursor.Decode(&group); err != nil {
+			return err
+		}
+
+		_, err = collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": group.IDs[1:]}})
+		if err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// providerArticleIDUp prefixes the numeric incrowd IDs of the stored
+// articles with their provider, as the pipeline stores them now. It can
+// not be reverted, the prefixed IDs look like the IDs of the feeds.
+func providerArticleIDUp(ctx context.Context, db *mongo.Database) error {
+	provider := bson.M{"$ifNull": bson.A{"$provider", legacyProvider}}
+	_, err := db.Collection(collectionName).UpdateMany(ctx,
+		bson.M{"article_id": bson.M{"$regex": "^[0-9]+$"}},
+		bson.A{bson.M{"$set": bson.M{
+			"article_id": bson.M{"$concat": bson.A{provider, "-", "$article_id"}},
+			"provider":   provider,
+		}}})
+
+	return err
+}
+
+// noop is the Down of migrations that cannot be reverted.
+func noop(context.Context, *mongo.Database) error {
+	return nil
+}
+
+// splitList converts a comma separated string field to an array of
+// trimmed non-empty values, other types are kept.
+func splitList(field string) bson.M {
+	return ifType(field, "string", bson.M{"$filter": bson.M{
+		"input": bson.M{"$map": bson.M{
+			"input": bson.M{"$split": bson.A{field, ","}},
+			"in":    bson.M{"$trim": bson.M{"input": "$$this"}},
+		}},
+		"cond": bson.M{"$ne": bson.A{"$$this", ""}},
+	}})
+}
+
+// joinList converts an array field to a comma separated string.
+func joinList(field string) bson.M {
+	return ifType(field, "array", bson.M{"$reduce": bson.M{
+		"input":        field,
+		"initialValue": "",
+		"in": bson.M{"$cond": bson.A{
+			bson.M{"$eq": bson.A{"$$value", ""}},
+			"$$this",
+			bson.M{"$concat": bson.A{"$$value", ",", "$$this"}},
+		}},
+	}})
+}
+
+// parseDate converts a string field in PublishDateLayout to a date,
+// invalid or empty dates are removed.
+func parseDate(field string) bson.M {
+	return ifType(field, "string", bson.M{"$dateFromString": bson.M{
+		"dateString": field,
+		"format":     publishDateFormat,
+		"timezone":   "UTC",
+		"onError":    "$$REMOVE",
+		"onNull":     "$$REMOVE",
+	}})
+}
+
+// formatDate converts a date field to a string in PublishDateLayout.
+func formatDate(field string) bson.M {
+	return ifType(field, "date", bson.M{"$dateToString": bson.M{
+		"date":     field,
+		"format":   publishDateFormat,
+		"timezone": "UTC",
+	}})
+}
+
+func ifType(field, bsonType string, then bson.M) bson.M {
+	return bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$type": field}, bsonType}},
+		then,
+		field,
+	}}
+}
@@ -0,0 +1,171 @@
+package mongo
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const migrationCollectionName = "migrations"
+
+// Migration is a versioned change of the schema of the stored documents.
+// Up and Down must be idempotent, they may be run again if the service
+// stops before the migration is recorded.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db *mongo.Database) error
+	Down    func(ctx context.Context, db *mongo.Database) error
+}
+
+// MigrationStatus is a known migration and when it was applied, AppliedAt
+// is nil for pending migrations.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// appliedMigration is a document of the migrations collection.
+type appliedMigration struct {
+	Version   int       `bson:"version"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// Migrator applies the migrations to the database of a Repository and
+// tracks them in the migrations collection.
+type Migrator struct {
+	db         *mongo.Database
+	migrations []Migration
+}
+
+// Migrator returns the migrator of the schema of the repository.
+func (r *Repository) Migrator() *Migrator {
+	return newMigrator(r.db.Database(r.databaseName), migrations)
+}
+
+func newMigrator(db *mongo.Database, list []Migration) *Migrator {
+	sorted := make([]Migration, len(list))
+	copy(sorted, list)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	return &Migrator{db: db, migrations: sorted}
+}
+
+// Up applies the pending migrations in order and returns them.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		if err = migration.Up(ctx, m.db); err != nil {
+			return done, errors.Wrapf(err, "migration %d %s", migration.Version, migration.Name)
+		}
+
+		_, err = m.db.Collection(migrationCollectionName).InsertOne(ctx, appliedMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now().UTC(),
+		})
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return done, err
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down reverts the last steps applied migrations, newest first, and
+// returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		if err = migration.Down(ctx, m.db); err != nil {
+			return done, errors.Wrapf(err, "migration %d %s", migration.Version, migration.Name)
+		}
+
+		_, err = m.db.Collection(migrationCollectionName).DeleteOne(ctx, bson.M{"version": migration.Version})
+		if err != nil {
+			return done, err
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Status returns every known migration, oldest first.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		s := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if a, ok := applied[migration.Version]; ok {
+			appliedAt := a.AppliedAt.UTC()
+			s.AppliedAt = &appliedAt
+		}
+
+		status = append(status, s)
+	}
+
+	return status, nil
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedMigration, error) {
+	collection := m.db.Collection(migrationCollectionName)
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []appliedMigration
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]appliedMigration, len(results))
+	for _, a := range results {
+		applied[a.Version] = a
+	}
+
+	return applied, nil
+}
@@ -12,19 +12,72 @@ import (
 // ArticleNews is a structure of article to be stored
 type ArticleNews struct {
 	ArticleID         string              `bson:"article_id"`
+	Provider          string              `bson:"provider,omitempty"`
 	ClubName          string              `bson:"club_name"`
 	ClubWebsiteURL    string              `bson:"club_website_url"`
+	ArticleURL        string              `bson:"article_url,omitempty"`
 	Title             string              `bson:"title"`
 	Subtitle          string              `bson:"subtitle,omitempty"`
 	BodyText          string              `bson:"body_text,omitempty"`
-	GalleryImageURLs  string              `bson:"gallery_image_urls,omitempty"`
+	Body              *Body               `bson:"body,omitempty"`
+	GalleryImageURLs  []string            `bson:"gallery_image_urls,omitempty"`
 	VideoURL          string              `bson:"video_url,omitempty"`
-	Taxonomies        string              `bson:"taxonomies,omitempty"`
+	Taxonomies        []string            `bson:"taxonomies,omitempty"`
 	TeaserText        string              `bson:"teaser_text,omitempty"`
 	ThumbnailImageURL string              `bson:"thumbnail_image_url,omitempty"`
-	PublishDate       string              `bson:"publish_date,omitempty"`
+	PublishDate       time.Time           `bson:"publish_date,omitempty"`
+	LastUpdateDate    string              `bson:"last_update_date,omitempty"`
 	IsPublished       bool                `bson:"is_published,omitempty"`
+	ContentHash       string              `bson:"content_hash,omitempty"`
 	CreateAt          primitive.Timestamp `bson:"create_at"`
+	DeletedAt         *time.Time          `bson:"deleted_at,omitempty"`
+	ArchivedAt        *time.Time          `bson:"archived_at,omitempty"`
+}
+
+// Body is the rich text of an article.
+type Body struct {
+	Blocks []Block `bson:"blocks"`
+}
+
+type Block struct {
+	Type    string   `bson:"type"`
+	Level   int      `bson:"level,omitempty"`
+	Inlines []Inline `bson:"inlines,omitempty"`
+	Src     string   `bson:"src,omitempty"`
+	Alt     string   `bson:"alt,omitempty"`
+}
+
+type Inline struct {
+	Type     string   `bson:"type"`
+	Text     string   `bson:"text,omitempty"`
+	Href     string   `bson:"href,omitempty"`
+	Children []Inline `bson:"children,omitempty"`
+}
+
+// Revision is a previous version of an article, stored next to the article collection.
+type Revision struct {
+	ArticleID  string      `bson:"article_id"`
+	Number     int         `bson:"revision"`
+	Article    ArticleNews `bson:"article"`
+	ReplacedAt time.Time   `bson:"replaced_at"`
+}
+
+// DeadLetter is an item of a provider that could not be stored.
+type DeadLetter struct {
+	ArticleID   string    `bson:"article_id"`
+	Provider    string    `bson:"provider"`
+	ArticleURL  string    `bson:"article_url"`
+	IsPublished bool      `bson:"is_published,omitempty"`
+	Stage       string    `bson:"stage"`
+	Error       string    `bson:"error"`
+	Attempts    int       `bson:"attempts"`
+	FailedAt    time.Time `bson:"failed_at"`
+}
+
+// scoredArticleNews is an ArticleNews found by a $text query.
+type scoredArticleNews struct {
+	ArticleNews `bson:",inline"`
+	Score       float64 `bson:"score"`
 }
 
 func (a *ArticleNews) createAt() time.Time {
@@ -34,21 +87,31 @@ func (a *ArticleNews) createAt() time.Time {
 func parseToBusinessArticleNews(result ArticleNews) internal.ArticleNews {
 	article := internal.ArticleNews{
 		NewsID:            result.ArticleID,
+		Provider:          result.Provider,
 		ClubName:          result.ClubName,
 		ClubWebsiteURL:    result.ClubWebsiteURL,
-		ArticleURL:        result.ArticleID,
+		ArticleURL:        result.ArticleURL,
 		Title:             result.Title,
 		Subtitle:          result.Subtitle,
 		BodyText:          result.BodyText,
+		Body:              parseToBusinessBody(result.Body),
 		GalleryImageURLs:  result.GalleryImageURLs,
 		VideoURL:          result.VideoURL,
 		Taxonomies:        result.Taxonomies,
 		TeaserText:        result.TeaserText,
 		ThumbnailImageURL: result.ThumbnailImageURL,
-		PublishDate:       result.PublishDate,
+		PublishDate:       result.PublishDate.UTC(),
+		LastUpdateDate:    result.LastUpdateDate,
 		IsPublished:       result.IsPublished,
 		CreateAt:          result.createAt(),
 	}
+	if result.DeletedAt != nil {
+		article.DeletedAt = result.DeletedAt.UTC()
+	}
+
+	if result.ArchivedAt != nil {
+		article.ArchivedAt = result.ArchivedAt.UTC()
+	}
 
 	return article
 }
@@ -56,22 +119,144 @@ func parseToBusinessArticleNews(result ArticleNews) internal.ArticleNews {
 func parseToArticleNewsDB(article internal.ArticleNews) ArticleNews {
 	a := ArticleNews{
 		ArticleID:         article.NewsID,
+		Provider:          article.Provider,
 		ClubName:          article.ClubName,
 		ClubWebsiteURL:    article.ClubWebsiteURL,
+		ArticleURL:        article.ArticleURL,
 		Title:             article.Title,
 		Subtitle:          article.Subtitle,
 		BodyText:          article.BodyText,
+		Body:              parseToBodyDB(article.Body),
 		GalleryImageURLs:  article.GalleryImageURLs,
 		VideoURL:          article.VideoURL,
 		Taxonomies:        article.Taxonomies,
 		TeaserText:        article.TeaserText,
 		ThumbnailImageURL: article.ThumbnailImageURL,
 		PublishDate:       article.PublishDate,
+		LastUpdateDate:    article.LastUpdateDate,
 		IsPublished:       article.IsPublished,
+		ContentHash:       article.Hash(),
 		CreateAt: primitive.Timestamp{
 			T: uint32(article.CreateAt.Unix()),
 		},
 	}
+	if article.IsDeleted() {
+		deletedAt := article.DeletedAt.UTC()
+		a.DeletedAt = &deletedAt
+	}
+
+	if article.IsArchived() {
+		archivedAt := article.ArchivedAt.UTC()
+		a.ArchivedAt = &archivedAt
+	}
 
 	return a
 }
+
+func parseToBusinessRevision(result Revision) internal.Revision {
+	return internal.Revision{
+		Number:     result.Number,
+		Article:    parseToBusinessArticleNews(result.Article),
+		ReplacedAt: result.ReplacedAt.UTC(),
+	}
+}
+
+func parseToBusinessBody(body *Body) internal.Body {
+	if body == nil {
+		return internal.Body{}
+	}
+
+	blocks := make([]internal.Block, 0, len(body.Blocks))
+	for _, b := range body.Blocks {
+		blocks = append(blocks, internal.Block{
+			Type:    internal.BlockType(b.Type),
+			Level:   b.Level,
+			Inlines: parseToBusinessInlines(b.Inlines),
+			Src:     b.Src,
+			Alt:     b.Alt,
+		})
+	}
+
+	return internal.Body{Blocks: blocks}
+}
+
+func parseToBusinessInlines(inlines []Inline) []internal.Inline {
+	if len(inlines) == 0 {
+		return nil
+	}
+
+	result := make([]internal.Inline, 0, len(inlines))
+	for _, in := range inlines {
+		result = append(result, internal.Inline{
+			Type:     internal.InlineType(in.Type),
+			Text:     in.Text,
+			Href:     in.Href,
+			Children: parseToBusinessInlines(in.Children),
+		})
+	}
+
+	return result
+}
+
+func parseToBodyDB(body internal.Body) *Body {
+	if body.IsEmpty() {
+		return nil
+	}
+
+	blocks := make([]Block, 0, len(body.Blocks))
+	for _, b := range body.Blocks {
+		blocks = append(blocks, Block{
+			Type:    string(b.Type),
+			Level:   b.Level,
+			Inlines: parseToInlinesDB(b.Inlines),
+			Src:     b.Src,
+			Alt:     b.Alt,
+		})
+	}
+
+	return &Body{Blocks: blocks}
+}
+
+func parseToInlinesDB(inlines []internal.Inline) []Inline {
+	if len(inlines) == 0 {
+		return nil
+	}
+
+	result := make([]Inline, 0, len(inlines))
+	for _, in := range inlines {
+		result = append(result, Inline{
+			Type:     string(in.Type),
+			Text:     in.Text,
+			Href:     in.Href,
+			Children: parseToInlinesDB(in.Children),
+		})
+	}
+
+	return result
+}
+
+func parseToBusinessDeadLetter(result DeadLetter) internal.DeadLetter {
+	return internal.DeadLetter{
+		ArticleID:   result.ArticleID,
+		Provider:    result.Provider,
+		ArticleURL:  result.ArticleURL,
+		IsPublished: result.IsPublished,
+		Stage:       result.Stage,
+		Error:       result.Error,
+		Attempts:    result.Attempts,
+		FailedAt:    result.FailedAt.UTC(),
+	}
+}
+
+func parseToDeadLetterDB(deadLetter internal.DeadLetter) DeadLetter {
+	return DeadLetter{
+		ArticleID:   deadLetter.ArticleID,
+		Provider:    deadLetter.Provider,
+		ArticleURL:  deadLetter.ArticleURL,
+		IsPublished: deadLetter.IsPublished,
+		Stage:       deadLetter.Stage,
+		Error:       deadLetter.Error,
+		Attempts:    deadLetter.Attempts,
+		FailedAt:    deadLetter.FailedAt.UTC(),
+	}
+}
@@ -0,0 +1,35 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+)
+
+func Test_parseToBodyDB(t *testing.T) {
+	body := internal.Body{Blocks: []internal.Block{
+		{Type: internal.BlockHeading, Level: 2, Inlines: []internal.Inline{{Type: internal.InlineText, Text: "Reaction"}}},
+		{Type: internal.BlockParagraph, Inlines: []internal.Inline{
+			{Type: internal.InlineText, Text: "Read the "},
+			{Type: internal.InlineLink, Href: "https://www.dbu.dk", Children: []internal.Inline{
+				{Type: internal.InlineStrong, Children: []internal.Inline{{Type: internal.InlineText, Text: "report"}}},
+			}},
+		}},
+		{Type: internal.BlockImage, Src: "https://example.com/a.jpg", Alt: "Thomas Frank"},
+	}}
+
+	assert.Equal(t, body, parseToBusinessBody(parseToBodyDB(body)))
+	assert.Nil(t, parseToBodyDB(internal.Body{}))
+	assert.Equal(t, internal.Body{}, parseToBusinessBody(nil))
+}
+
+func Test_parseToBusinessArticleNews(t *testing.T) {
+	article := internal.NewArticle()
+	article.ArticleURL = "https://www.brentfordfc.com/news/2022/june/three-wins"
+
+	got := parseToBusinessArticleNews(parseToArticleNewsDB(article))
+	assert.Equal(t, article.ArticleURL, got.ArticleURL)
+	assert.Equal(t, article.NewsID, got.NewsID)
+}
@@ -2,20 +2,36 @@ package mongo
 
 import (
 	"context"
-	"log"
+	"strconv"
+	"time"
 
 	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
 
 	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"gopkg.in/mgo.v2/bson"
 
 	"github.com/patriciabonaldy/sports-news/internal"
 	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/search"
 )
 
-const collectionName = "article"
+const (
+	collectionName         = "article"
+	revisionCollectionName = "article_revision"
+	archiveCollectionName  = "article_archive"
+	deadCollectionName     = "dead_letter"
+	textIndexName          = "article_text"
+	// maxWriteAttempts bounds the retries of a write that lost a race
+	// with a concurrent writer of the same article.
+	maxWriteAttempts = 5
+)
+
+// errWriteConflict is returned when the stored article changed between
+// the read and the write of an Upsert.
+var errWriteConflict = errors.New("article was written concurrently")
 
 // Repository is a mongo EventRepository implementation.
 type Repository struct {
@@ -49,32 +65,140 @@ func NewDBStorage(ctx context.Context, cfg *config.Database, log logger.Logger)
 	}, nil
 }
 
+// Close disconnects the client, waiting for the operations in progress
+// until the context is done.
+func (r *Repository) Close(ctx context.Context) error {
+	return r.db.Disconnect(ctx)
+}
+
+// GetArticles returns every stored article, an empty slice when there
+// are none.
 func (r *Repository) GetArticles(ctx context.Context) ([]internal.ArticleNews, error) {
-	cursor, err := r.getCollection(collectionName).Find(context.TODO(), bson.M{})
+	cursor, err := r.getCollection(collectionName).Find(ctx, bson.M{})
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []ArticleNews
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	articles := make([]internal.ArticleNews, 0, len(results))
+	for _, result := range results {
+		articles = append(articles, parseToBusinessArticleNews(result))
 	}
 
-	var results []internal.ArticleNews
-	for cursor.Next(context.TODO()) {
-		//Create a value into which the single document can be decoded
-		var elem ArticleNews
-		err = cursor.Decode(&elem)
+	return articles, nil
+}
+
+// GetArticlesPage returns a page of articles using keyset pagination
+// over the sort field and article_id.
+func (r *Repository) GetArticlesPage(ctx context.Context, query internal.ArticleQuery) (*internal.ArticlePage, error) {
+	filter := toFilter(query.Filter)
+	if query.Cursor != "" {
+		cursor, err := internal.DecodeCursor(query.Cursor, query.Sort)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 
-		results = append(results, parseToBusinessArticleNews(elem))
+		after, err := afterCursor(query.Sort, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		filter = bson.M{"$and": bson.A{filter, after}}
+	}
+
+	direction := -1
+	if query.Sort.Ascending {
+		direction = 1
+	}
+
+	sort := bson.D{{Key: string(query.Sort.Field), Value: direction}, {Key: "article_id", Value: direction}}
+	var cursor *mongo.Cursor
+	var err error
+	if query.IncludeArchived {
+		cursor, err = r.getCollection(collectionName).Aggregate(ctx, bson.A{
+			bson.M{"$match": filter},
+			bson.M{"$unionWith": bson.M{
+				"coll":     archiveCollectionName,
+				"pipeline": bson.A{bson.M{"$match": filter}},
+			}},
+			bson.M{"$sort": sort},
+			bson.M{"$limit": query.Limit + 1},
+		})
+	} else {
+		opts := options.Find().SetSort(sort).SetLimit(int64(query.Limit + 1))
+		cursor, err = r.getCollection(collectionName).Find(ctx, filter, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []ArticleNews
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	page := &internal.ArticlePage{
+		Articles: make([]internal.ArticleNews, 0, len(results)),
+		Limit:    query.Limit,
+		Sort:     query.Sort,
+	}
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+		page.Next = cursorOf(query.Sort, results[len(results)-1]).Encode()
+	}
+
+	for _, result := range results {
+		page.Articles = append(page.Articles, parseToBusinessArticleNews(result))
+	}
+
+	return page, nil
+}
+
+// Search runs a $text query over the text index of the collection,
+// best scored articles first.
+func (r *Repository) Search(ctx context.Context, query internal.SearchQuery) ([]internal.SearchResult, error) {
+	terms := search.Terms(query.Text)
+	if len(terms) == 0 {
+		return nil, internal.ErrInvalidSearch
 	}
 
-	if err := cursor.Err(); err != nil {
-		log.Fatal(err)
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "article_id", Value: 1}}).
+		SetLimit(int64(query.Limit))
+	cursor, err := r.getCollection(collectionName).Find(ctx, bson.M{
+		"$text":        bson.M{"$search": query.Text},
+		"deleted_at":   nil,
+		"is_published": true,
+	}, opts)
+	if err != nil {
+		return nil, err
 	}
+	defer cursor.Close(ctx)
 
-	//Close the cursor once finished
-	cursor.Close(ctx)
+	var results []scoredArticleNews
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
 
-	return results, nil
+	found := make([]internal.SearchResult, 0, len(results))
+	for _, result := range results {
+		article := parseToBusinessArticleNews(result.ArticleNews)
+		found = append(found, internal.SearchResult{
+			Article:    article,
+			Score:      result.Score,
+			Highlights: search.Highlight(terms, search.Fields(article)),
+		})
+	}
+
+	return found, nil
 }
 
 func (r *Repository) GetArticleByID(ctx context.Context, articleID string) (*internal.ArticleNews, error) {
@@ -94,16 +218,444 @@ func (r *Repository) GetArticleByID(ctx context.Context, articleID string) (*int
 	return &art, nil
 }
 
+// Save stores the article, replacing the stored one with the same
+// article_id. It is idempotent, saving the same article concurrently
+// leaves a single document.
 func (r *Repository) Save(ctx context.Context, article internal.ArticleNews) error {
 	articleDB := parseToArticleNewsDB(article)
-	_, err := r.getCollection(collectionName).InsertOne(ctx, articleDB)
+	opts := options.Replace().SetUpsert(true)
+	var err error
+	for attempt := 0; attempt < maxWriteAttempts; attempt++ {
+		_, err = r.getCollection(collectionName).
+			ReplaceOne(ctx, bson.M{"article_id": article.NewsID}, articleDB, opts)
+		// two upserts of a new article_id may both try to insert it, the
+		// one that loses the race replaces the inserted document instead.
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// toFilter translates the filter into a query over the indexed fields.
+func toFilter(filter internal.ArticleFilter) bson.M {
+	query := bson.M{}
+	if filter.ClubName != "" {
+		query["club_name"] = filter.ClubName
+	}
+
+	if filter.Taxonomy != "" {
+		query["taxonomies"] = filter.Taxonomy
+	}
+
+	if filter.Published != nil {
+		// is_published is omitted when false, so unpublished articles
+		// are the ones where it is not true.
+		if *filter.Published {
+			query["is_published"] = true
+		} else {
+			query["is_published"] = bson.M{"$ne": true}
+		}
+	}
+
+	if filter.Deleted != nil {
+		if *filter.Deleted {
+			query["deleted_at"] = bson.M{"$ne": nil}
+		} else {
+			query["deleted_at"] = nil
+		}
+	}
+
+	publishDate := bson.M{}
+	if !filter.From.IsZero() {
+		publishDate["$gte"] = filter.From.UTC()
+	}
+
+	if !filter.To.IsZero() {
+		publishDate["$lt"] = filter.To.UTC()
+	}
+
+	if len(publishDate) > 0 {
+		query["publish_date"] = publishDate
+	}
+
+	return query
+}
+
+func afterCursor(sort internal.Sort, cursor internal.Cursor) (bson.M, error) {
+	var key interface{}
+	if sort.Field == internal.SortByCreateAt {
+		seconds, err := strconv.ParseUint(cursor.Key, 10, 32)
+		if err != nil {
+			return nil, internal.ErrInvalidCursor
+		}
+
+		key = primitive.Timestamp{T: uint32(seconds)}
+	} else {
+		publishDate, err := internal.ParsePublishDate(cursor.Key)
+		if err != nil {
+			return nil, internal.ErrInvalidCursor
+		}
+
+		key = publishDate
+	}
+
+	operator := "$lt"
+	if sort.Ascending {
+		operator = "$gt"
+	}
+
+	field := string(sort.Field)
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{operator: key}},
+		bson.M{field: key, "article_id": bson.M{operator: cursor.ID}},
+	}}, nil
+}
+
+func cursorOf(sort internal.Sort, article ArticleNews) internal.Cursor {
+	key := internal.FormatPublishDate(article.PublishDate)
+	if sort.Field == internal.SortByCreateAt {
+		key = strconv.FormatUint(uint64(article.CreateAt.T), 10)
+	}
+
+	return internal.Cursor{
+		Sort: sort.String(),
+		Key:  key,
+		ID:   article.ArticleID,
+	}
+}
+
+// Upsert inserts the article or replaces the stored one when its
+// LastUpdateDate or content hash changed. The original create_at is kept,
+// archived articles are updated in the archive. Concurrent upserts of the same article are serialised by the unique
+// article_id index and by replacing only the version that was read, the
+// writer that loses the race reads the article again.
+func (r *Repository) Upsert(ctx context.Context, article internal.ArticleNews) (internal.UpsertResult, error) {
+	for attempt := 0; attempt < maxWriteAttempts; attempt++ {
+		result, err := r.upsert(ctx, article)
+		if !errors.Is(err, errWriteConflict) {
+			return result, err
+		}
+	}
+
+	return internal.UpsertUnchanged, errWriteConflict
+}
+
+func (r *Repository) upsert(ctx context.Context, article internal.ArticleNews) (internal.UpsertResult, error) {
+	articleDB := parseToArticleNewsDB(article)
+	collection := r.getCollection(collectionName)
+	var stored ArticleNews
+	err := collection.FindOne(ctx, bson.M{"article_id": article.NewsID}).Decode(&stored)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// an archived article that is still listed stays in the archive
+		collection = r.getCollection(archiveCollectionName)
+		err = collection.FindOne(ctx, bson.M{"article_id": article.NewsID}).Decode(&stored)
+		articleDB.ArchivedAt = stored.ArchivedAt
+	}
+
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		_, err = r.getCollection(collectionName).InsertOne(ctx, articleDB)
+		if mongo.IsDuplicateKeyError(err) {
+			return internal.UpsertUnchanged, errWriteConflict
+		}
+
+		if err != nil {
+			return internal.UpsertUnchanged, err
+		}
+
+		return internal.UpsertInserted, nil
+	}
+
+	if err != nil {
+		return internal.UpsertUnchanged, err
+	}
+
+	previous := parseToBusinessArticleNews(stored)
+	if !article.ChangedFrom(previous) {
+		if article.FillsBody(previous) {
+			// articles stored before their body was parsed
+			articleDB.CreateAt = stored.CreateAt
+			_, err = collection.ReplaceOne(ctx, versionFilter(stored), articleDB)
+		} else if stored.Provider != articleDB.Provider {
+			// articles stored before they recorded their provider
+			_, err = collection.UpdateOne(ctx,
+				bson.M{"article_id": article.NewsID}, bson.M{"$set": bson.M{"provider": articleDB.Provider}})
+		}
+
+		return internal.UpsertUnchanged, err
+	}
+
+	// the revision is written first, so that a failed replace never loses
+	// the stored version, and removed when the replace does not happen.
+	revision, err := r.saveRevision(ctx, stored)
+	if err != nil {
+		return internal.UpsertUnchanged, err
+	}
+
+	articleDB.CreateAt = stored.CreateAt
+	result, err := collection.ReplaceOne(ctx, versionFilter(stored), articleDB)
+	if err == nil && result.MatchedCount == 0 {
+		err = errWriteConflict
+	}
+
+	if err != nil {
+		if deleteErr := r.deleteRevision(ctx, stored.ArticleID, revision); deleteErr != nil {
+			return internal.UpsertUnchanged, errors.Wrapf(err, "delete revision %d: %v", revision, deleteErr)
+		}
+
+		return internal.UpsertUnchanged, err
+	}
+
+	return internal.UpsertUpdated, nil
+}
+
+// versionFilter matches the article only while it is the stored version.
+// Empty fields are not stored, null matches them.
+func versionFilter(stored ArticleNews) bson.M {
+	filter := bson.M{"article_id": stored.ArticleID, "content_hash": nil, "last_update_date": nil, "deleted_at": nil}
+	if stored.ContentHash != "" {
+		filter["content_hash"] = stored.ContentHash
+	}
+
+	if stored.LastUpdateDate != "" {
+		filter["last_update_date"] = stored.LastUpdateDate
+	}
+
+	if stored.DeletedAt != nil {
+		filter["deleted_at"] = stored.DeletedAt
+	}
+
+	return filter
+}
+
+// Withdraw marks deleted the articles missing from the list of the
+// provider and unpublished the ones it flags.
+func (r *Repository) Withdraw(ctx context.Context, withdrawal internal.Withdrawal) (internal.WithdrawResult, error) {
+	window := bson.M{
+		"provider":     withdrawal.Provider,
+		"publish_date": bson.M{"$gte": withdrawal.Since.UTC()},
+	}
+
+	var result internal.WithdrawResult
+	deleted := bson.M{"article_id": bson.M{"$nin": nonNil(withdrawal.Listed)}, "deleted_at": nil}
+	for k, v := range window {
+		deleted[k] = v
+	}
+
+	res, err := r.getCollection(collectionName).UpdateMany(ctx, deleted,
+		bson.M{"$set": bson.M{"deleted_at": withdrawal.At.UTC()}})
+	if err != nil {
+		return result, err
+	}
+
+	result.Deleted = int(res.ModifiedCount)
+	if len(withdrawal.Unpublished) == 0 {
+		return result, nil
+	}
+
+	unpublished := bson.M{"article_id": bson.M{"$in": withdrawal.Unpublished}, "is_published": true}
+	for k, v := range window {
+		unpublished[k] = v
+	}
+
+	res, err = r.getCollection(collectionName).UpdateMany(ctx, unpublished,
+		bson.M{"$unset": bson.M{"is_published": ""}})
+	if err != nil {
+		return result, err
+	}
+
+	result.Unpublished = int(res.ModifiedCount)
+
+	return result, nil
+}
+
+// Archive moves the articles selected by the query to the archive
+// collection. Articles are copied before they are deleted, so a failed
+// run is completed by the next one.
+func (r *Repository) Archive(ctx context.Context, query internal.ArchiveQuery) ([]internal.ArticleNews, error) {
+	filter := bson.M{"publish_date": bson.M{"$lt": query.Before.UTC()}}
+	if query.ClubName != "" {
+		filter["club_name"] = query.ClubName
+	} else if len(query.ExceptClubs) > 0 {
+		filter["club_name"] = bson.M{"$nin": query.ExceptClubs}
+	}
+
+	cursor, err := r.getCollection(collectionName).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var archived []internal.ArticleNews
+	archivedAt := query.At.UTC()
+	opts := options.Replace().SetUpsert(true)
+	for cursor.Next(ctx) {
+		var result ArticleNews
+		if err = cursor.Decode(&result); err != nil {
+			return archived, err
+		}
+
+		result.ArchivedAt = &archivedAt
+		_, err = r.getCollection(archiveCollectionName).
+			ReplaceOne(ctx, bson.M{"article_id": result.ArticleID}, result, opts)
+		if err != nil {
+			return archived, err
+		}
+
+		_, err = r.getCollection(collectionName).DeleteOne(ctx, bson.M{"article_id": result.ArticleID})
+		if err != nil {
+			return archived, err
+		}
+
+		archived = append(archived, parseToBusinessArticleNews(result))
+	}
+
+	return archived, cursor.Err()
+}
+
+// nonNil keeps $nin from receiving a null list.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+
+	return values
+}
+
+// GetRevisions returns the previous versions of the article, oldest first.
+func (r *Repository) GetRevisions(ctx context.Context, articleID string) ([]internal.Revision, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "revision", Value: 1}})
+	cursor, err := r.getCollection(revisionCollectionName).Find(ctx, bson.M{"article_id": articleID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []Revision
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	revisions := make([]internal.Revision, 0, len(results))
+	for _, result := range results {
+		revisions = append(revisions, parseToBusinessRevision(result))
+	}
+
+	return revisions, nil
+}
+
+// SaveDeadLetter stores the dead letter, replacing the one of the same
+// article of the provider.
+func (r *Repository) SaveDeadLetter(ctx context.Context, deadLetter internal.DeadLetter) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.getCollection(deadCollectionName).
+		ReplaceOne(ctx, deadLetterFilter(deadLetter.Provider, deadLetter.ArticleID), parseToDeadLetterDB(deadLetter), opts)
+
+	return err
+}
+
+// GetDeadLetters returns the dead letters, oldest first.
+func (r *Repository) GetDeadLetters(ctx context.Context) ([]internal.DeadLetter, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "failed_at", Value: 1}, {Key: "article_id", Value: 1}, {Key: "provider", Value: 1}})
+	cursor, err := r.getCollection(deadCollectionName).Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []DeadLetter
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	deadLetters := make([]internal.DeadLetter, 0, len(results))
+	for _, result := range results {
+		deadLetters = append(deadLetters, parseToBusinessDeadLetter(result))
+	}
+
+	return deadLetters, nil
+}
+
+func (r *Repository) GetDeadLetter(ctx context.Context, provider, articleID string) (*internal.DeadLetter, error) {
+	var result DeadLetter
+	err := r.getCollection(deadCollectionName).
+		FindOne(ctx, deadLetterFilter(provider, articleID)).Decode(&result)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal.ErrDeadLetterNotFound
+		}
+
+		return nil, err
+	}
+
+	deadLetter := parseToBusinessDeadLetter(result)
+	return &deadLetter, nil
+}
+
+func (r *Repository) DeleteDeadLetter(ctx context.Context, provider, articleID string) error {
+	result, err := r.getCollection(deadCollectionName).DeleteOne(ctx, deadLetterFilter(provider, articleID))
 	if err != nil {
 		return err
 	}
 
+	if result.DeletedCount == 0 {
+		return internal.ErrDeadLetterNotFound
+	}
+
 	return nil
 }
 
+// deadLetterFilter matches the dead letter of an article of a provider.
+func deadLetterFilter(provider, articleID string) bson.M {
+	return bson.M{"provider": provider, "article_id": articleID}
+}
+
+// saveRevision stores the replaced version with the next revision
+// number. Numbers are unique per article, a number taken by a concurrent
+// write is retried with the following one.
+func (r *Repository) saveRevision(ctx context.Context, stored ArticleNews) (int, error) {
+	var err error
+	for attempt := 0; attempt < maxWriteAttempts; attempt++ {
+		var number int
+		if number, err = r.lastRevision(ctx, stored.ArticleID); err != nil {
+			return 0, err
+		}
+
+		_, err = r.getCollection(revisionCollectionName).InsertOne(ctx, Revision{
+			ArticleID:  stored.ArticleID,
+			Number:     number + 1,
+			Article:    stored,
+			ReplacedAt: time.Now().UTC(),
+		})
+		if !mongo.IsDuplicateKeyError(err) {
+			return number + 1, err
+		}
+	}
+
+	return 0, err
+}
+
+// deleteRevision removes a revision saved for a replace that did not happen.
+func (r *Repository) deleteRevision(ctx context.Context, articleID string, number int) error {
+	_, err := r.getCollection(revisionCollectionName).
+		DeleteOne(ctx, bson.M{"article_id": articleID, "revision": number})
+
+	return err
+}
+
+func (r *Repository) lastRevision(ctx context.Context, articleID string) (int, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "revision", Value: -1}})
+	var last Revision
+	err := r.getCollection(revisionCollectionName).
+		FindOne(ctx, bson.M{"article_id": articleID}, opts).Decode(&last)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+
+	return last.Number, err
+}
+
 func (r *Repository) getCollection(collectionName string) *mongo.Collection {
 	return r.db.Database(r.databaseName).Collection(collectionName, nil)
 }
@@ -6,7 +6,10 @@ import (
 	"log"
 	"os"
 	"reflect"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
 
@@ -14,11 +17,13 @@ import (
 	"github.com/ory/dockertest/v3/docker"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/patriciabonaldy/sports-news/internal"
 	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/storagetest"
 )
 
 var db *mongo.Client
@@ -74,6 +79,531 @@ func TestRepository_Save(t *testing.T) {
 	assert.Equal(t, reflect.DeepEqual(want, got), true)
 }
 
+func TestRepository_Upsert(t *testing.T) {
+	repo := &Repository{
+		databaseName: "test_upsert",
+		db:           db,
+	}
+
+	ctx := context.Background()
+	article := mockArticle()
+	article.LastUpdateDate = "2022-06-14 08:00:00"
+
+	got, err := repo.Upsert(ctx, article)
+	require.NoError(t, err)
+	assert.Equal(t, internal.UpsertInserted, got)
+
+	got, err = repo.Upsert(ctx, article)
+	require.NoError(t, err)
+	assert.Equal(t, internal.UpsertUnchanged, got)
+
+	article.Title = "Pontus explains again"
+	got, err = repo.Upsert(ctx, article)
+	require.NoError(t, err)
+	assert.Equal(t, internal.UpsertUpdated, got)
+
+	stored, err := repo.GetArticleByID(ctx, article.NewsID)
+	require.NoError(t, err)
+	assert.Equal(t, article.Title, stored.Title)
+
+	revisions, err := repo.GetRevisions(ctx, article.NewsID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, 1, revisions[0].Number)
+	assert.Equal(t, mockArticle().Title, revisions[0].Article.Title)
+}
+
+func TestRepository_GetArticlesPage(t *testing.T) {
+	repo := &Repository{
+		databaseName: "test_page",
+		db:           db,
+	}
+
+	ctx := context.Background()
+	for _, date := range []string{"2022-06-13 08:00:00", "2022-06-14 08:00:00", "2022-06-15 08:00:00"} {
+		article := mockArticle()
+		article.PublishDate, _ = internal.ParsePublishDate(date)
+		require.NoError(t, repo.Save(ctx, article))
+	}
+
+	query := internal.ArticleQuery{Limit: 2, Sort: internal.DefaultSort()}
+	got, err := repo.GetArticlesPage(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, got.Articles, 2)
+	assert.Equal(t, "2022-06-15 08:00:00", internal.FormatPublishDate(got.Articles[0].PublishDate))
+	assert.Equal(t, "2022-06-14 08:00:00", internal.FormatPublishDate(got.Articles[1].PublishDate))
+	require.NotEmpty(t, got.Next)
+
+	query.Cursor = got.Next
+	got, err = repo.GetArticlesPage(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, got.Articles, 1)
+	assert.Equal(t, "2022-06-13 08:00:00", internal.FormatPublishDate(got.Articles[0].PublishDate))
+	assert.Empty(t, got.Next)
+}
+
+func TestRepository_GetArticlesPage_Filter(t *testing.T) {
+	repo := &Repository{
+		databaseName: "test_filter",
+		db:           db,
+	}
+
+	ctx := context.Background()
+	articles := []struct {
+		club        string
+		taxonomy    string
+		publishDate string
+		isPublished bool
+	}{
+		{club: "Brentford", taxonomy: "History", publishDate: "2022-06-13 08:00:00", isPublished: true},
+		{club: "Brentford", taxonomy: "Players", publishDate: "2022-06-14 08:00:00", isPublished: false},
+		{club: "Arsenal", taxonomy: "History", publishDate: "2022-06-15 08:00:00", isPublished: true},
+	}
+	for _, a := range articles {
+		article := mockArticle()
+		article.ClubName = a.club
+		article.Taxonomies = []string{a.taxonomy, "News"}
+		article.PublishDate, _ = internal.ParsePublishDate(a.publishDate)
+		article.IsPublished = a.isPublished
+		require.NoError(t, repo.Save(ctx, article))
+	}
+
+	unpublished := false
+	tests := []struct {
+		name   string
+		filter internal.ArticleFilter
+		want   int
+	}{
+		{name: "club", filter: internal.ArticleFilter{ClubName: "Brentford"}, want: 2},
+		{name: "taxonomy", filter: internal.ArticleFilter{Taxonomy: "History"}, want: 2},
+		{name: "unpublished", filter: internal.ArticleFilter{Published: &unpublished}, want: 1},
+		{
+			name: "publish date",
+			filter: internal.ArticleFilter{
+				From: time.Date(2022, 6, 14, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2022, 6, 15, 0, 0, 0, 0, time.UTC),
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetArticlesPage(ctx, internal.ArticleQuery{
+				Filter: tt.filter,
+				Limit:  internal.DefaultPageLimit,
+				Sort:   internal.DefaultSort(),
+			})
+			require.NoError(t, err)
+			assert.Len(t, got.Articles, tt.want)
+		})
+	}
+}
+
+func TestMigrator_Up(t *testing.T) {
+	repo := &Repository{
+		databaseName: "test_migrate",
+		db:           db,
+	}
+
+	ctx := context.Background()
+	legacy := bson.M{
+		"article_id":         "641772",
+		"club_name":          "Brentford",
+		"title":              "Three wins for international Bees yesterday",
+		"taxonomies":         "Players, International",
+		"gallery_image_urls": "https://www.brentfordfc.com/a.jpg,https://www.brentfordfc.com/b.jpg",
+		"publish_date":       "2022-06-15 10:00:00",
+		"content_hash":       "legacy",
+	}
+	_, err := repo.getCollection(collectionName).InsertOne(ctx, legacy)
+	require.NoError(t, err)
+	_, err = repo.getCollection(revisionCollectionName).InsertOne(ctx, bson.M{
+		"article_id": "641772",
+		"revision":   1,
+		"article":    legacy,
+	})
+	require.NoError(t, err)
+
+	migrator := repo.Migrator()
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrations))
+
+	// applied migrations are not run again
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	got, err := repo.GetArticleByID(ctx, "641772")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Players", "International"}, got.Taxonomies)
+	assert.Equal(t, []string{"https://www.brentfordfc.com/a.jpg", "https://www.brentfordfc.com/b.jpg"}, got.GalleryImageURLs)
+	assert.Equal(t, time.Date(2022, 6, 15, 10, 0, 0, 0, time.UTC), got.PublishDate)
+
+	var raw bson.M
+	err = repo.getCollection(collectionName).FindOne(ctx, bson.M{"article_id": "641772"}).Decode(&raw)
+	require.NoError(t, err)
+	assert.NotContains(t, raw, "content_hash")
+
+	revisions, err := repo.GetRevisions(ctx, "641772")
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, []string{"Players", "International"}, revisions[0].Article.Taxonomies)
+	assert.Equal(t, time.Date(2022, 6, 15, 10, 0, 0, 0, time.UTC), revisions[0].Article.PublishDate)
+
+	status, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, status, len(migrations))
+	for _, s := range status {
+		assert.NotNil(t, s.AppliedAt, s.Name)
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	repo := &Repository{
+		databaseName: "test_migrate_down",
+		db:           db,
+	}
+
+	ctx := context.Background()
+	article := mockArticle()
+	article.ArticleURL = "https://www.brentfordfc.com/news/2022/june/three-wins"
+	article.PublishDate = time.Date(2022, 6, 15, 10, 0, 0, 0, time.UTC)
+	article.Taxonomies = []string{"Players", "International"}
+	require.NoError(t, repo.Save(ctx, article))
+
+	migrator := repo.Migrator()
+	_, err := migrator.Up(ctx)
+	require.NoError(t, err)
+
+	got, err := repo.GetArticleByID(ctx, article.NewsID)
+	require.NoError(t, err)
+	assert.Equal(t, article.ArticleURL, got.ArticleURL)
+
+	reverted, err := migrator.Down(ctx, len(migrations))
+	require.NoError(t, err)
+	require.Len(t, reverted, len(migrations))
+	assert.Equal(t, migrations[len(migrations)-1].Version, reverted[0].Version)
+
+	var raw bson.M
+	err = repo.getCollection(collectionName).FindOne(ctx, bson.M{"article_id": article.NewsID}).Decode(&raw)
+	require.NoError(t, err)
+	assert.NotContains(t, raw, "article_url")
+	assert.Equal(t, "2022-06-15 10:00:00", raw["publish_date"])
+	assert.Equal(t, "Players,International", raw["taxonomies"])
+
+	status, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	for _, s := range status {
+		assert.Nil(t, s.AppliedAt, s.Name)
+	}
+}
+
+func TestRepository_EnsureIndexes(t *testing.T) {
+	repo := &Repository{
+		databaseName: "test_indexes",
+		db:           db,
+	}
+
+	ctx := context.Background()
+	report, err := repo.IndexReport(ctx)
+	require.NoError(t, err)
+	for _, s := range report {
+		assert.Equal(t, IndexMissing, s.State, s.Name)
+	}
+
+	// creating the indexes again does nothing
+	require.NoError(t, repo.EnsureIndexes(ctx))
+	require.NoError(t, repo.EnsureIndexes(ctx))
+
+	_, err = repo.getCollection(collectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "title", Value: 1}},
+		Options: options.Index().SetName("title"),
+	})
+	require.NoError(t, err)
+
+	report, err = repo.IndexReport(ctx)
+	require.NoError(t, err)
+	require.Len(t, report, len(indexes())+1)
+	for _, s := range report[:len(indexes())] {
+		assert.Equal(t, IndexOK, s.State, s.Name)
+	}
+	assert.Equal(t, IndexStatus{Collection: collectionName, Name: "title", State: IndexExtra}, report[len(indexes())])
+
+	article := mockArticle()
+	_, err = repo.getCollection(collectionName).InsertOne(ctx, parseToArticleNewsDB(article))
+	require.NoError(t, err)
+	_, err = repo.getCollection(collectionName).InsertOne(ctx, parseToArticleNewsDB(article))
+	require.True(t, mongo.IsDuplicateKeyError(err))
+}
+
+func TestMigrator_duplicatedArticles(t *testing.T) {
+	repo := &Repository{
+		databaseName: "test_migrate_duplicates",
+		db:           db,
+	}
+
+	ctx := context.Background()
+	article := mockArticle()
+	_, err := repo.getCollection(collectionName).InsertOne(ctx, parseToArticleNewsDB(article))
+	require.NoError(t, err)
+	article.Title = "Pontus explains how fatherhood has calmed him down"
+	_, err = repo.getCollection(collectionName).InsertOne(ctx, parseToArticleNewsDB(article))
+	require.NoError(t, err)
+
+	require.NoError(t, duplicatedArticlesUp(ctx, repo.db.Database(repo.databaseName)))
+	require.NoError(t, repo.EnsureIndexes(ctx))
+
+	count, err := repo.getCollection(collectionName).CountDocuments(ctx, bson.M{"article_id": article.NewsID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	got, err := repo.GetArticleByID(ctx, article.NewsID)
+	require.NoError(t, err)
+	assert.Equal(t, article.Title, got.Title)
+}
+
+func TestRepository_Save_parallel(t *testing.T) {
+	repo := &Repository{
+		databaseName: "test_save_parallel",
+		db:           db,
+	}
+
+	ctx := context.Background()
+	require.NoError(t, repo.EnsureIndexes(ctx))
+
+	article := mockArticle()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repo.Save(ctx, article))
+		}()
+	}
+	wg.Wait()
+
+	count, err := repo.getCollection(collectionName).CountDocuments(ctx, bson.M{"article_id": article.NewsID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestRepository_Upsert_parallel(t *testing.T) {
+	repo := &Repository{
+		databaseName: "test_upsert_parallel",
+		db:           db,
+	}
+
+	ctx := context.Background()
+	require.NoError(t, repo.EnsureIndexes(ctx))
+
+	article := mockArticle()
+	results := make(chan internal.UpsertResult, 20)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(results); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			version := article
+			version.Title = fmt.Sprintf("version %d", i%4)
+			result, err := repo.Upsert(ctx, version)
+			assert.NoError(t, err)
+			results <- result
+		}(i)
+	}
+	wg.Wait()
+	close(results)
+
+	var inserted, updated int
+	for result := range results {
+		switch result {
+		case internal.UpsertInserted:
+			inserted++
+		case internal.UpsertUpdated:
+			updated++
+		}
+	}
+	assert.Equal(t, 1, inserted)
+
+	count, err := repo.getCollection(collectionName).CountDocuments(ctx, bson.M{"article_id": article.NewsID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// every update stored the replaced version once, numbered in order.
+	// The revisions of writers that lost the race are removed, which can
+	// leave gaps in the numbers.
+	revisions, err := repo.GetRevisions(ctx, article.NewsID)
+	require.NoError(t, err)
+	require.Len(t, revisions, updated)
+	for i := 1; i < len(revisions); i++ {
+		assert.Less(t, revisions[i-1].Number, revisions[i].Number)
+	}
+}
+
+func TestRepository_Withdraw(t *testing.T) {
+	repo := &Repository{
+		databaseName: "test_withdraw",
+		db:           db,
+	}
+
+	ctx := context.Background()
+	for i, day := range []int{1, 14, 15, 16} {
+		article := mockArticle()
+		article.NewsID = strconv.Itoa(i + 1)
+		article.Provider = "brentford"
+		article.IsPublished = true
+		article.PublishDate = time.Date(2022, 6, day, 8, 0, 0, 0, time.UTC)
+		require.NoError(t, repo.Save(ctx, article))
+	}
+
+	withdrawal := internal.Withdrawal{
+		Provider:    "brentford",
+		Listed:      []string{"2", "4"},
+		Unpublished: []string{"4"},
+		Since:       time.Date(2022, 6, 14, 8, 0, 0, 0, time.UTC),
+		At:          time.Date(2022, 6, 17, 8, 0, 0, 0, time.UTC),
+	}
+	got, err := repo.Withdraw(ctx, withdrawal)
+	require.NoError(t, err)
+	assert.Equal(t, internal.WithdrawResult{Deleted: 1, Unpublished: 1}, got)
+
+	got, err = repo.Withdraw(ctx, withdrawal)
+	require.NoError(t, err)
+	assert.Equal(t, internal.WithdrawResult{}, got)
+
+	article, err := repo.GetArticleByID(ctx, "3")
+	require.NoError(t, err)
+	assert.Equal(t, withdrawal.At, article.DeletedAt)
+
+	published, notDeleted := true, false
+	page, err := repo.GetArticlesPage(ctx, internal.ArticleQuery{
+		Filter: internal.ArticleFilter{Published: &published, Deleted: &notDeleted},
+		Limit:  internal.DefaultPageLimit,
+		Sort:   internal.DefaultSort(),
+	})
+	require.NoError(t, err)
+	require.Len(t, page.Articles, 2)
+	assert.Equal(t, "2", page.Articles[0].NewsID)
+	assert.Equal(t, "1", page.Articles[1].NewsID)
+
+	// listing the article again restores it
+	article.DeletedAt = time.Time{}
+	result, err := repo.Upsert(ctx, *article)
+	require.NoError(t, err)
+	assert.Equal(t, internal.UpsertUpdated, result)
+}
+
+func TestRepository_Archive(t *testing.T) {
+	repo := &Repository{
+		databaseName: "test_archive",
+		db:           db,
+	}
+
+	ctx := context.Background()
+	require.NoError(t, repo.EnsureIndexes(ctx))
+	for _, article := range []struct {
+		id   string
+		club string
+		year int
+	}{
+		{id: "1", club: "Brentford", year: 2021},
+		{id: "2", club: "Brentford", year: 2022},
+		{id: "3", club: "Arsenal", year: 2021},
+	} {
+		a := mockArticle()
+		a.NewsID = article.id
+		a.ClubName = article.club
+		a.PublishDate = time.Date(article.year, 6, 15, 8, 0, 0, 0, time.UTC)
+		require.NoError(t, repo.Save(ctx, a))
+	}
+
+	at := time.Date(2022, 12, 31, 3, 0, 0, 0, time.UTC)
+	query := internal.ArchiveQuery{
+		ClubName: "Brentford",
+		Before:   time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		At:       at,
+	}
+	got, err := repo.Archive(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "1", got[0].NewsID)
+	assert.Equal(t, at, got[0].ArchivedAt)
+
+	got, err = repo.Archive(ctx, query)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = repo.GetArticleByID(ctx, "1")
+	assert.Equal(t, internal.ErrArticleNotFound, err)
+
+	page, err := repo.GetArticlesPage(ctx, internal.ArticleQuery{
+		Limit: internal.DefaultPageLimit,
+		Sort:  internal.DefaultSort(),
+	})
+	require.NoError(t, err)
+	assert.Len(t, page.Articles, 2)
+
+	// pages over the article and the archive collections
+	page, err = repo.GetArticlesPage(ctx, internal.ArticleQuery{
+		Limit:           2,
+		Sort:            internal.DefaultSort(),
+		IncludeArchived: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, page.Articles, 2)
+	require.NotEmpty(t, page.Next)
+	next, err := repo.GetArticlesPage(ctx, internal.ArticleQuery{
+		Limit:           2,
+		Sort:            internal.DefaultSort(),
+		Cursor:          page.Next,
+		IncludeArchived: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, next.Articles, 1)
+	assert.Equal(t, "3", page.Articles[1].NewsID)
+	assert.Equal(t, "1", next.Articles[0].NewsID)
+	assert.True(t, next.Articles[0].IsArchived())
+}
+
+func TestRepository_Search(t *testing.T) {
+	repo := &Repository{
+		databaseName: "test_search",
+		db:           db,
+	}
+
+	ctx := context.Background()
+	require.NoError(t, repo.EnsureIndexes(ctx))
+
+	title := mockArticle()
+	title.Title = "Toney called up by England"
+	body := mockArticle()
+	body.BodyText = "Ivan Toney has been named in the England squad"
+	require.NoError(t, repo.Save(ctx, body))
+	require.NoError(t, repo.Save(ctx, title))
+	require.NoError(t, repo.Save(ctx, mockArticle()))
+
+	got, err := repo.Search(ctx, internal.SearchQuery{Text: "toney", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, title.NewsID, got[0].Article.NewsID)
+	assert.Equal(t, []string{"<em>Toney</em> called up by England"}, got[0].Highlights)
+	assert.Greater(t, got[0].Score, got[1].Score)
+}
+
+func TestRepository_conformance(t *testing.T) {
+	var n int
+	storagetest.Run(t, func(t *testing.T) internal.Storage {
+		n++
+		repo := &Repository{
+			databaseName: fmt.Sprintf("test_conformance_%d", n),
+			db:           db,
+		}
+		require.NoError(t, repo.EnsureIndexes(context.Background()))
+
+		return repo
+	})
+}
+
 func mockArticle() internal.ArticleNews {
 	article := internal.NewArticle()
 
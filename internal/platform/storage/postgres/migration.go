@@ -0,0 +1,257 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationFile matches the names of the migration files, like
+// 0001_create_articles.up.sql.
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned change of the schema, Up and Down are the SQL
+// statements that apply and revert it.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus is a known migration and when it was applied, AppliedAt
+// is nil for pending migrations.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Migrator applies the embedded migrations to the database of a
+// Repository and tracks them in the schema_migration table. Every
+// migration runs in a transaction together with its record.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// Migrator returns the migrator of the schema of the repository.
+func (r *Repository) Migrator() *Migrator {
+	return &Migrator{db: r.db, migrations: r.migrations}
+}
+
+// loadMigrations reads the up and down files of the migrations, sorted
+// by version.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, file := range files {
+		name := file[len("migrations/"):]
+		match := migrationFile.FindStringSubmatch(name)
+		if match == nil {
+			return nil, errors.Errorf("migration %s: invalid name", name)
+		}
+
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, errors.Errorf("migration %d %s: up and down are required", m.Version, m.Name)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Up applies the pending migrations in order and returns them.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	if err := m.createTable(ctx); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		applied, err := m.apply(ctx, migration)
+		if err != nil {
+			return done, errors.Wrapf(err, "migration %d %s", migration.Version, migration.Name)
+		}
+
+		if applied {
+			done = append(done, migration)
+		}
+	}
+
+	return done, nil
+}
+
+// Down reverts the last steps applied migrations, newest first, and
+// returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if err := m.createTable(ctx); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		reverted, err := m.revert(ctx, migration)
+		if err != nil {
+			return done, errors.Wrapf(err, "migration %d %s", migration.Version, migration.Name)
+		}
+
+		if reverted {
+			done = append(done, migration)
+		}
+	}
+
+	return done, nil
+}
+
+// Status returns every known migration and when it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.createTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migration`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err = rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+
+		applied[version] = appliedAt.UTC()
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	status := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		s := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			s.AppliedAt = &appliedAt
+		}
+
+		status = append(status, s)
+	}
+
+	return status, nil
+}
+
+func (m *Migrator) createTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migration (
+		version    integer PRIMARY KEY,
+		name       text NOT NULL,
+		applied_at timestamptz NOT NULL
+	)`)
+
+	return err
+}
+
+// apply runs the migration unless it was applied, the table lock keeps
+// the instances that start together from applying it twice.
+func (m *Migrator) apply(ctx context.Context, migration Migration) (bool, error) {
+	return m.inLockedTx(ctx, migration.Version, func(tx *sql.Tx, applied bool) (bool, error) {
+		if applied {
+			return false, nil
+		}
+
+		if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+			return false, err
+		}
+
+		_, err := tx.ExecContext(ctx, `INSERT INTO schema_migration (version, name, applied_at) VALUES ($1, $2, $3)`,
+			migration.Version, migration.Name, time.Now().UTC())
+
+		return err == nil, err
+	})
+}
+
+func (m *Migrator) revert(ctx context.Context, migration Migration) (bool, error) {
+	return m.inLockedTx(ctx, migration.Version, func(tx *sql.Tx, applied bool) (bool, error) {
+		if !applied {
+			return false, nil
+		}
+
+		if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+			return false, err
+		}
+
+		_, err := tx.ExecContext(ctx, `DELETE FROM schema_migration WHERE version = $1`, migration.Version)
+
+		return err == nil, err
+	})
+}
+
+// inLockedTx runs fn in a transaction holding the lock of the
+// schema_migration table, with whether the version is applied.
+func (m *Migrator) inLockedTx(ctx context.Context, version int, fn func(tx *sql.Tx, applied bool) (bool, error)) (bool, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `LOCK TABLE schema_migration IN EXCLUSIVE MODE`); err != nil {
+		return false, err
+	}
+
+	var applied bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migration WHERE version = $1)`, version).
+		Scan(&applied)
+	if err != nil {
+		return false, err
+	}
+
+	done, err := fn(tx, applied)
+	if err != nil || !done {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS article_revision;
+DROP TABLE IF EXISTS article_archive;
+DROP TABLE IF EXISTS article;
@@ -0,0 +1,52 @@
+-- article_id is compared bytewise, like the cursors of the other storages.
+-- Articles without publish date keep the zero time of Go, so that they
+-- sort first like in the other storages.
+CREATE TABLE article (
+    article_id          text COLLATE "C" PRIMARY KEY,
+    provider            text NOT NULL DEFAULT '',
+    club_name           text NOT NULL DEFAULT '',
+    club_website_url    text NOT NULL DEFAULT '',
+    article_url         text NOT NULL DEFAULT '',
+    title               text NOT NULL DEFAULT '',
+    subtitle            text NOT NULL DEFAULT '',
+    body_text           text NOT NULL DEFAULT '',
+    body                jsonb,
+    gallery_image_urls  text[] NOT NULL DEFAULT '{}',
+    video_url           text NOT NULL DEFAULT '',
+    taxonomies          text[] NOT NULL DEFAULT '{}',
+    teaser_text         text NOT NULL DEFAULT '',
+    thumbnail_image_url text NOT NULL DEFAULT '',
+    publish_date        timestamptz NOT NULL,
+    last_update_date    text NOT NULL DEFAULT '',
+    is_published        boolean NOT NULL DEFAULT false,
+    content_hash        text NOT NULL DEFAULT '',
+    create_at           timestamptz NOT NULL,
+    deleted_at          timestamptz,
+    archived_at         timestamptz,
+    -- weighted like search.Weights: title A, subtitle B, teaser C, body D
+    search              tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', title), 'A') ||
+        setweight(to_tsvector('simple', subtitle), 'B') ||
+        setweight(to_tsvector('simple', teaser_text), 'C') ||
+        setweight(to_tsvector('simple', body_text), 'D')
+    ) STORED
+);
+
+CREATE INDEX article_club_name ON article (club_name);
+CREATE INDEX article_publish_date ON article (publish_date DESC, article_id DESC);
+CREATE INDEX article_create_at ON article (create_at DESC, article_id DESC);
+CREATE INDEX article_taxonomies ON article USING gin (taxonomies);
+CREATE INDEX article_provider_publish_date ON article (provider, publish_date);
+CREATE INDEX article_search ON article USING gin (search);
+
+CREATE TABLE article_archive (LIKE article INCLUDING ALL);
+
+-- revisions keep the columns of the replaced version of the article
+CREATE TABLE article_revision (
+    LIKE article INCLUDING DEFAULTS,
+    revision    integer NOT NULL,
+    replaced_at timestamptz NOT NULL,
+    PRIMARY KEY (article_id, revision)
+);
+
+ALTER TABLE article_revision DROP COLUMN search;
@@ -0,0 +1,15 @@
+-- items of the providers that could not be stored, one per article of a
+-- provider, the articles of two providers can share an id
+CREATE TABLE dead_letter (
+    article_id   text COLLATE "C" NOT NULL,
+    provider     text COLLATE "C" NOT NULL,
+    article_url  text NOT NULL DEFAULT '',
+    is_published boolean NOT NULL DEFAULT false,
+    stage        text NOT NULL DEFAULT '',
+    error        text NOT NULL DEFAULT '',
+    attempts     integer NOT NULL DEFAULT 0,
+    failed_at    timestamptz NOT NULL,
+    PRIMARY KEY (provider, article_id)
+);
+
+CREATE INDEX dead_letter_failed_at ON dead_letter (failed_at, article_id, provider);
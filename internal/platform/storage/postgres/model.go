@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+)
+
+// articleColumns are the columns of an article in the article, archive
+// and revision tables, in the order of articleRow.fields.
+var articleColumns = []string{
+	"article_id", "provider", "club_name", "club_website_url", "article_url", "title", "subtitle",
+	"body_text", "body", "gallery_image_urls", "video_url", "taxonomies", "teaser_text",
+	"thumbnail_image_url", "publish_date", "last_update_date", "is_published", "content_hash",
+	"create_at", "deleted_at", "archived_at",
+}
+
+// deadLetterColumns are the columns of the dead_letter table, in the
+// order of scanDeadLetter and deadLetterValues.
+var deadLetterColumns = []string{
+	"article_id", "provider", "article_url", "is_published", "stage", "error", "attempts", "failed_at",
+}
+
+// articleRow is a row of the article table.
+type articleRow struct {
+	ArticleID         string
+	Provider          string
+	ClubName          string
+	ClubWebsiteURL    string
+	ArticleURL        string
+	Title             string
+	Subtitle          string
+	BodyText          string
+	Body              []byte
+	GalleryImageURLs  pq.StringArray
+	VideoURL          string
+	Taxonomies        pq.StringArray
+	TeaserText        string
+	ThumbnailImageURL string
+	PublishDate       time.Time
+	LastUpdateDate    string
+	IsPublished       bool
+	ContentHash       string
+	CreateAt          time.Time
+	DeletedAt         sql.NullTime
+	ArchivedAt        sql.NullTime
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// fields returns the pointers to the fields of the row, to scan or to
+// write them in the order of articleColumns.
+func (a *articleRow) fields() []interface{} {
+	return []interface{}{
+		&a.ArticleID, &a.Provider, &a.ClubName, &a.ClubWebsiteURL, &a.ArticleURL, &a.Title, &a.Subtitle,
+		&a.BodyText, &a.Body, &a.GalleryImageURLs, &a.VideoURL, &a.Taxonomies, &a.TeaserText,
+		&a.ThumbnailImageURL, &a.PublishDate, &a.LastUpdateDate, &a.IsPublished, &a.ContentHash,
+		&a.CreateAt, &a.DeletedAt, &a.ArchivedAt,
+	}
+}
+
+// values returns the values of the fields of the row, in the order of
+// articleColumns.
+func (a *articleRow) values() []interface{} {
+	return []interface{}{
+		a.ArticleID, a.Provider, a.ClubName, a.ClubWebsiteURL, a.ArticleURL, a.Title, a.Subtitle,
+		a.BodyText, a.Body, a.GalleryImageURLs, a.VideoURL, a.Taxonomies, a.TeaserText,
+		a.ThumbnailImageURL, a.PublishDate, a.LastUpdateDate, a.IsPublished, a.ContentHash,
+		a.CreateAt, a.DeletedAt, a.ArchivedAt,
+	}
+}
+
+func scanArticle(row scanner, extra ...interface{}) (articleRow, error) {
+	var a articleRow
+	err := row.Scan(append(a.fields(), extra...)...)
+
+	return a, err
+}
+
+func parseToBusinessArticleNews(row articleRow) (internal.ArticleNews, error) {
+	article := internal.ArticleNews{
+		NewsID:            row.ArticleID,
+		Provider:          row.Provider,
+		ClubName:          row.ClubName,
+		ClubWebsiteURL:    row.ClubWebsiteURL,
+		ArticleURL:        row.ArticleURL,
+		Title:             row.Title,
+		Subtitle:          row.Subtitle,
+		BodyText:          row.BodyText,
+		GalleryImageURLs:  list(row.GalleryImageURLs),
+		VideoURL:          row.VideoURL,
+		Taxonomies:        list(row.Taxonomies),
+		TeaserText:        row.TeaserText,
+		ThumbnailImageURL: row.ThumbnailImageURL,
+		PublishDate:       row.PublishDate.UTC(),
+		LastUpdateDate:    row.LastUpdateDate,
+		IsPublished:       row.IsPublished,
+		CreateAt:          row.CreateAt.UTC(),
+	}
+	if len(row.Body) > 0 {
+		if err := json.Unmarshal(row.Body, &article.Body); err != nil {
+			return article, err
+		}
+	}
+
+	if row.DeletedAt.Valid {
+		article.DeletedAt = row.DeletedAt.Time.UTC()
+	}
+
+	if row.ArchivedAt.Valid {
+		article.ArchivedAt = row.ArchivedAt.Time.UTC()
+	}
+
+	return article, nil
+}
+
+// parseToArticleRow converts the article, create_at is kept with a
+// precision of seconds like the cursors.
+func parseToArticleRow(article internal.ArticleNews) (articleRow, error) {
+	row := articleRow{
+		ArticleID:         article.NewsID,
+		Provider:          article.Provider,
+		ClubName:          article.ClubName,
+		ClubWebsiteURL:    article.ClubWebsiteURL,
+		ArticleURL:        article.ArticleURL,
+		Title:             article.Title,
+		Subtitle:          article.Subtitle,
+		BodyText:          article.BodyText,
+		GalleryImageURLs:  nonNil(article.GalleryImageURLs),
+		VideoURL:          article.VideoURL,
+		Taxonomies:        nonNil(article.Taxonomies),
+		TeaserText:        article.TeaserText,
+		ThumbnailImageURL: article.ThumbnailImageURL,
+		PublishDate:       article.PublishDate.UTC(),
+		LastUpdateDate:    article.LastUpdateDate,
+		IsPublished:       article.IsPublished,
+		ContentHash:       article.Hash(),
+		CreateAt:          article.CreateAt.UTC().Truncate(time.Second),
+	}
+	if !article.Body.IsEmpty() {
+		body, err := json.Marshal(article.Body)
+		if err != nil {
+			return row, err
+		}
+
+		row.Body = body
+	}
+
+	if article.IsDeleted() {
+		row.DeletedAt = sql.NullTime{Time: article.DeletedAt.UTC(), Valid: true}
+	}
+
+	if article.IsArchived() {
+		row.ArchivedAt = sql.NullTime{Time: article.ArchivedAt.UTC(), Valid: true}
+	}
+
+	return row, nil
+}
+
+// list returns nil for empty arrays, like the other storages.
+func list(values pq.StringArray) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	return values
+}
+
+// nonNil keeps nil slices from being written as NULL arrays.
+func nonNil(values []string) pq.StringArray {
+	if values == nil {
+		return pq.StringArray{}
+	}
+
+	return values
+}
+
+func scanDeadLetter(row scanner) (internal.DeadLetter, error) {
+	var d internal.DeadLetter
+	err := row.Scan(&d.ArticleID, &d.Provider, &d.ArticleURL, &d.IsPublished, &d.Stage, &d.Error, &d.Attempts, &d.FailedAt)
+	d.FailedAt = d.FailedAt.UTC()
+
+	return d, err
+}
+
+func deadLetterValues(d internal.DeadLetter) []interface{} {
+	return []interface{}{d.ArticleID, d.Provider, d.ArticleURL, d.IsPublished, d.Stage, d.Error, d.Attempts, d.FailedAt.UTC()}
+}
+
+// columns joins the names of the columns of a statement.
+func columns(names []string) string {
+	return strings.Join(names, ", ")
+}
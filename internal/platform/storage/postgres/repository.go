@@ -0,0 +1,671 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/search"
+)
+
+const (
+	tableName         = "article"
+	revisionTableName = "article_revision"
+	archiveTableName  = "article_archive"
+	deadTableName     = "dead_letter"
+	// maxWriteAttempts bounds the retries of an insert that lost a race
+	// with a concurrent insert of the same article.
+	maxWriteAttempts = 5
+)
+
+// errWriteConflict is returned when a concurrent Upsert inserted the
+// article first.
+var errWriteConflict = errors.New("article was written concurrently")
+
+var (
+	selectArticle = "SELECT " + columns(articleColumns) + " FROM " + tableName
+	// saveArticle inserts the article or replaces every column of the
+	// stored one.
+	saveArticle   = saveStatement(tableName)
+	insertArticle = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (article_id) DO NOTHING",
+		tableName, columns(articleColumns), placeholders(1, len(articleColumns)))
+	// hotTable and archiveTable hold the stored version of an article, an
+	// archived article that is still listed is updated in the archive.
+	hotTable     = articleTable{name: tableName, save: saveArticle, saveRevision: saveRevisionStatement(tableName)}
+	archiveTable = articleTable{name: archiveTableName, save: saveStatement(archiveTableName),
+		saveRevision: saveRevisionStatement(archiveTableName)}
+	// archiveArticles moves the selected articles to the archive table in
+	// a single statement, %s is the condition of the selected articles.
+	archiveArticles = fmt.Sprintf(`WITH moved AS (DELETE FROM %s WHERE %%s RETURNING %s)
+		INSERT INTO %s (%s) SELECT %s, $1::timestamptz FROM moved
+		ON CONFLICT (article_id) DO UPDATE SET %s
+		RETURNING %s`,
+		tableName, columns(articleColumns), archiveTableName, columns(articleColumns),
+		columns(articleColumns[:len(articleColumns)-1]), excluded(articleColumns[1:]), columns(articleColumns))
+	selectDeadLetter = "SELECT " + columns(deadLetterColumns) + " FROM " + deadTableName
+	saveDeadLetter   = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (provider, article_id) DO UPDATE SET %s",
+		deadTableName, columns(deadLetterColumns), placeholders(1, len(deadLetterColumns)), excluded(deadLetterColumns[2:]))
+)
+
+// articleTable is a table of articles with its write statements.
+type articleTable struct {
+	name         string
+	save         string
+	saveRevision string
+}
+
+func saveStatement(table string) string {
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (article_id) DO UPDATE SET %s",
+		table, columns(articleColumns), placeholders(1, len(articleColumns)), excluded(articleColumns[1:]))
+}
+
+// saveRevisionStatement copies the stored version of the article with the
+// next revision number, the row lock of the article serialises the numbers.
+func saveRevisionStatement(table string) string {
+	return fmt.Sprintf(`INSERT INTO %[1]s (revision, replaced_at, %[2]s)
+		SELECT coalesce((SELECT max(revision) FROM %[1]s WHERE article_id = $1), 0) + 1, $2::timestamptz, %[2]s
+		FROM %[3]s WHERE article_id = $1`,
+		revisionTableName, columns(articleColumns), table)
+}
+
+// Repository is a postgres Storage implementation.
+type Repository struct {
+	db         *sql.DB
+	migrations []Migration
+	log        logger.Logger
+}
+
+var _ internal.Storage = &Repository{}
+
+// NewDBStorage initializes a postgres-based implementation of Storage.
+// The schema is created by its Migrator.
+func NewDBStorage(ctx context.Context, cfg *config.Postgres, log logger.Logger) (*Repository, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("postgres", cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Repository{
+		db:         db,
+		migrations: migrations,
+		log:        log,
+	}, nil
+}
+
+// Close closes the connections of the pool.
+func (r *Repository) Close() error {
+	return r.db.Close()
+}
+
+// GetArticles returns every stored article, an empty slice when there
+// are none.
+func (r *Repository) GetArticles(ctx context.Context) ([]internal.ArticleNews, error) {
+	rows, err := r.db.QueryContext(ctx, selectArticle)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanArticles(rows)
+}
+
+// GetArticlesPage returns a page of articles using keyset pagination
+// over the sort field and article_id.
+func (r *Repository) GetArticlesPage(ctx context.Context, query internal.ArticleQuery) (*internal.ArticlePage, error) {
+	where := toFilter(query.Filter)
+	direction := "DESC"
+	operator := "<"
+	if query.Sort.Ascending {
+		direction = "ASC"
+		operator = ">"
+	}
+
+	field := string(query.Sort.Field)
+	if query.Cursor != "" {
+		cursor, err := internal.DecodeCursor(query.Cursor, query.Sort)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := cursorKey(query.Sort, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		where.and(fmt.Sprintf("(%s, article_id) %s (%s, %s)", field, operator, where.arg(key), where.arg(cursor.ID)))
+	}
+
+	from := tableName
+	if query.IncludeArchived {
+		from = fmt.Sprintf("(SELECT %[1]s FROM %[2]s UNION ALL SELECT %[1]s FROM %[3]s) AS %[2]s",
+			columns(articleColumns), tableName, archiveTableName)
+	}
+
+	statement := fmt.Sprintf("SELECT %s FROM %s%s ORDER BY %s %s, article_id %s LIMIT %s",
+		columns(articleColumns), from, where, field, direction, direction, where.arg(query.Limit+1))
+	rows, err := r.db.QueryContext(ctx, statement, where.args...)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := scanArticles(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &internal.ArticlePage{
+		Articles: make([]internal.ArticleNews, 0, len(results)),
+		Limit:    query.Limit,
+		Sort:     query.Sort,
+	}
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+		page.Next = cursorOf(query.Sort, results[len(results)-1]).Encode()
+	}
+
+	page.Articles = append(page.Articles, results...)
+
+	return page, nil
+}
+
+// Search runs a prefix query over the search column of the articles,
+// ranked with the weights of search.Weights.
+func (r *Repository) Search(ctx context.Context, query internal.SearchQuery) ([]internal.SearchResult, error) {
+	terms := search.Terms(query.Text)
+	if len(terms) == 0 {
+		return nil, internal.ErrInvalidSearch
+	}
+
+	var limit interface{}
+	if query.Limit > 0 {
+		limit = query.Limit
+	}
+
+	statement := fmt.Sprintf(`SELECT %s, ts_rank($1::float4[], search, q) AS score
+		FROM %s, to_tsquery('simple', $2) AS q
+		WHERE search @@ q AND deleted_at IS NULL AND is_published ORDER BY score DESC, article_id LIMIT $3`,
+		columns(articleColumns), tableName)
+	rows, err := r.db.QueryContext(ctx, statement, rankWeights(), tsQuery(terms), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := make([]internal.SearchResult, 0)
+	for rows.Next() {
+		var score float64
+		row, err := scanArticle(rows, &score)
+		if err != nil {
+			return nil, err
+		}
+
+		article, err := parseToBusinessArticleNews(row)
+		if err != nil {
+			return nil, err
+		}
+
+		found = append(found, internal.SearchResult{
+			Article:    article,
+			Score:      score,
+			Highlights: search.Highlight(terms, search.Fields(article)),
+		})
+	}
+
+	return found, rows.Err()
+}
+
+func (r *Repository) GetArticleByID(ctx context.Context, articleID string) (*internal.ArticleNews, error) {
+	article, err := getArticle(ctx, r.db, selectArticle+" WHERE article_id = $1", articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &article, nil
+}
+
+// Save stores the article, replacing the stored one with the same
+// article_id.
+func (r *Repository) Save(ctx context.Context, article internal.ArticleNews) error {
+	row, err := parseToArticleRow(article)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, saveArticle, row.values()...)
+
+	return err
+}
+
+// Upsert inserts the article or replaces the stored one when its
+// LastUpdateDate or content hash changed. The original create_at is kept,
+// archived articles are updated in the archive. The stored article is
+// locked until the write, so concurrent upserts of the same article are
+// serialised.
+func (r *Repository) Upsert(ctx context.Context, article internal.ArticleNews) (internal.UpsertResult, error) {
+	for attempt := 0; attempt < maxWriteAttempts; attempt++ {
+		result, err := r.upsert(ctx, article)
+		if !errors.Is(err, errWriteConflict) {
+			return result, err
+		}
+	}
+
+	return internal.UpsertUnchanged, errWriteConflict
+}
+
+func (r *Repository) upsert(ctx context.Context, article internal.ArticleNews) (internal.UpsertResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return internal.UpsertUnchanged, err
+	}
+	defer tx.Rollback()
+
+	result, err := upsert(ctx, tx, article)
+	if err != nil {
+		return internal.UpsertUnchanged, err
+	}
+
+	return result, tx.Commit()
+}
+
+func upsert(ctx context.Context, tx *sql.Tx, article internal.ArticleNews) (internal.UpsertResult, error) {
+	table := hotTable
+	stored, err := lockArticle(ctx, tx, table, article.NewsID)
+	if errors.Is(err, internal.ErrArticleNotFound) {
+		table = archiveTable
+		stored, err = lockArticle(ctx, tx, table, article.NewsID)
+		article.ArchivedAt = stored.ArchivedAt
+	}
+
+	if errors.Is(err, internal.ErrArticleNotFound) {
+		row, err := parseToArticleRow(article)
+		if err != nil {
+			return internal.UpsertUnchanged, err
+		}
+
+		res, err := tx.ExecContext(ctx, insertArticle, row.values()...)
+		if err != nil {
+			return internal.UpsertUnchanged, err
+		}
+
+		if inserted, _ := res.RowsAffected(); inserted == 0 {
+			return internal.UpsertUnchanged, errWriteConflict
+		}
+
+		return internal.UpsertInserted, nil
+	}
+
+	if err != nil {
+		return internal.UpsertUnchanged, err
+	}
+
+	if !article.ChangedFrom(stored) {
+		if article.FillsBody(stored) {
+			return internal.UpsertUnchanged, replaceArticle(ctx, tx, table, article, stored)
+		}
+
+		if stored.Provider != article.Provider {
+			_, err = tx.ExecContext(ctx, "UPDATE "+table.name+" SET provider = $2 WHERE article_id = $1",
+				article.NewsID, article.Provider)
+		}
+
+		return internal.UpsertUnchanged, err
+	}
+
+	if _, err = tx.ExecContext(ctx, table.saveRevision, article.NewsID, time.Now().UTC()); err != nil {
+		return internal.UpsertUnchanged, err
+	}
+
+	if err = replaceArticle(ctx, tx, table, article, stored); err != nil {
+		return internal.UpsertUnchanged, err
+	}
+
+	return internal.UpsertUpdated, nil
+}
+
+// lockArticle reads the article stored in table and locks it until the
+// end of the transaction.
+func lockArticle(ctx context.Context, tx *sql.Tx, table articleTable, articleID string) (internal.ArticleNews, error) {
+	return getArticle(ctx, tx, "SELECT "+columns(articleColumns)+" FROM "+table.name+" WHERE article_id = $1 FOR UPDATE",
+		articleID)
+}
+
+// replaceArticle writes article over stored in table, keeping its CreateAt.
+func replaceArticle(ctx context.Context, tx *sql.Tx, table articleTable, article, stored internal.ArticleNews) error {
+	article.CreateAt = stored.CreateAt
+	row, err := parseToArticleRow(article)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, table.save, row.values()...)
+	return err
+}
+
+// Withdraw marks deleted the articles missing from the list of the
+// provider and unpublished the ones it flags.
+func (r *Repository) Withdraw(ctx context.Context, withdrawal internal.Withdrawal) (internal.WithdrawResult, error) {
+	var result internal.WithdrawResult
+	res, err := r.db.ExecContext(ctx, `UPDATE `+tableName+` SET deleted_at = $1
+		WHERE provider = $2 AND publish_date >= $3 AND deleted_at IS NULL AND NOT (article_id = ANY($4))`,
+		withdrawal.At.UTC(), withdrawal.Provider, withdrawal.Since.UTC(), nonNil(withdrawal.Listed))
+	if err != nil {
+		return result, err
+	}
+
+	deleted, _ := res.RowsAffected()
+	result.Deleted = int(deleted)
+	if len(withdrawal.Unpublished) == 0 {
+		return result, nil
+	}
+
+	res, err = r.db.ExecContext(ctx, `UPDATE `+tableName+` SET is_published = false
+		WHERE provider = $1 AND publish_date >= $2 AND is_published AND article_id = ANY($3)`,
+		withdrawal.Provider, withdrawal.Since.UTC(), pq.StringArray(withdrawal.Unpublished))
+	if err != nil {
+		return result, err
+	}
+
+	unpublished, _ := res.RowsAffected()
+	result.Unpublished = int(unpublished)
+
+	return result, nil
+}
+
+// Archive moves the articles selected by the query to the archive table.
+// Articles without publish date are never archived.
+func (r *Repository) Archive(ctx context.Context, query internal.ArchiveQuery) ([]internal.ArticleNews, error) {
+	where := &conditions{args: []interface{}{query.At.UTC()}}
+	where.and(fmt.Sprintf("publish_date < %s AND publish_date > %s", where.arg(query.Before.UTC()), where.arg(time.Time{})))
+	if query.ClubName != "" {
+		where.and("club_name = " + where.arg(query.ClubName))
+	} else if len(query.ExceptClubs) > 0 {
+		where.and(fmt.Sprintf("NOT (club_name = ANY(%s))", where.arg(pq.StringArray(query.ExceptClubs))))
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(archiveArticles, strings.Join(where.clauses, " AND ")), where.args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanArticles(rows)
+}
+
+// GetRevisions returns the previous versions of the article, oldest first.
+func (r *Repository) GetRevisions(ctx context.Context, articleID string) ([]internal.Revision, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf("SELECT %s, revision, replaced_at FROM %s WHERE article_id = $1 ORDER BY revision",
+		columns(articleColumns), revisionTableName), articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]internal.Revision, 0)
+	for rows.Next() {
+		var revision internal.Revision
+		row, err := scanArticle(rows, &revision.Number, &revision.ReplacedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if revision.Article, err = parseToBusinessArticleNews(row); err != nil {
+			return nil, err
+		}
+
+		revision.ReplacedAt = revision.ReplacedAt.UTC()
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
+
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// SaveDeadLetter stores the dead letter, replacing the one of the same
+// article of the provider.
+func (r *Repository) SaveDeadLetter(ctx context.Context, deadLetter internal.DeadLetter) error {
+	_, err := r.db.ExecContext(ctx, saveDeadLetter, deadLetterValues(deadLetter)...)
+
+	return err
+}
+
+// GetDeadLetters returns the dead letters, oldest first.
+func (r *Repository) GetDeadLetters(ctx context.Context) ([]internal.DeadLetter, error) {
+	rows, err := r.db.QueryContext(ctx, selectDeadLetter+" ORDER BY failed_at, article_id, provider")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deadLetters := make([]internal.DeadLetter, 0)
+	for rows.Next() {
+		deadLetter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	return deadLetters, rows.Err()
+}
+
+func (r *Repository) GetDeadLetter(ctx context.Context, provider, articleID string) (*internal.DeadLetter, error) {
+	deadLetter, err := scanDeadLetter(r.db.QueryRowContext(ctx, selectDeadLetter+" WHERE provider = $1 AND article_id = $2",
+		provider, articleID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, internal.ErrDeadLetterNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &deadLetter, nil
+}
+
+func (r *Repository) DeleteDeadLetter(ctx context.Context, provider, articleID string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM "+deadTableName+" WHERE provider = $1 AND article_id = $2",
+		provider, articleID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return internal.ErrDeadLetterNotFound
+	}
+
+	return nil
+}
+
+func getArticle(ctx context.Context, db queryer, query string, args ...interface{}) (internal.ArticleNews, error) {
+	row, err := scanArticle(db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return internal.ArticleNews{}, internal.ErrArticleNotFound
+	}
+
+	if err != nil {
+		return internal.ArticleNews{}, err
+	}
+
+	return parseToBusinessArticleNews(row)
+}
+
+// scanArticles reads and closes the rows, it returns an empty slice when
+// there are none.
+func scanArticles(rows *sql.Rows) ([]internal.ArticleNews, error) {
+	defer rows.Close()
+
+	results := make([]internal.ArticleNews, 0)
+	for rows.Next() {
+		row, err := scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		article, err := parseToBusinessArticleNews(row)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, article)
+	}
+
+	return results, rows.Err()
+}
+
+// conditions is the WHERE clause of a query and its arguments.
+type conditions struct {
+	clauses []string
+	args    []interface{}
+}
+
+// arg adds an argument and returns its placeholder.
+func (c *conditions) arg(value interface{}) string {
+	c.args = append(c.args, value)
+	return "$" + strconv.Itoa(len(c.args))
+}
+
+func (c *conditions) and(clause string) {
+	c.clauses = append(c.clauses, clause)
+}
+
+func (c *conditions) String() string {
+	if len(c.clauses) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(c.clauses, " AND ")
+}
+
+// toFilter translates the filter into conditions over the indexed columns.
+func toFilter(filter internal.ArticleFilter) *conditions {
+	where := &conditions{}
+	if filter.ClubName != "" {
+		where.and("club_name = " + where.arg(filter.ClubName))
+	}
+
+	if filter.Taxonomy != "" {
+		where.and(where.arg(filter.Taxonomy) + " = ANY(taxonomies)")
+	}
+
+	if filter.Published != nil {
+		where.and("is_published = " + where.arg(*filter.Published))
+	}
+
+	if filter.Deleted != nil {
+		if *filter.Deleted {
+			where.and("deleted_at IS NOT NULL")
+		} else {
+			where.and("deleted_at IS NULL")
+		}
+	}
+
+	if !filter.From.IsZero() {
+		where.and("publish_date >= " + where.arg(filter.From.UTC()))
+	}
+
+	if !filter.To.IsZero() {
+		where.and("publish_date < " + where.arg(filter.To.UTC()))
+	}
+
+	return where
+}
+
+func cursorKey(sort internal.Sort, cursor internal.Cursor) (time.Time, error) {
+	if sort.Field == internal.SortByCreateAt {
+		seconds, err := strconv.ParseInt(cursor.Key, 10, 64)
+		if err != nil {
+			return time.Time{}, internal.ErrInvalidCursor
+		}
+
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+
+	publishDate, err := internal.ParsePublishDate(cursor.Key)
+	if err != nil {
+		return time.Time{}, internal.ErrInvalidCursor
+	}
+
+	return publishDate, nil
+}
+
+func cursorOf(sort internal.Sort, article internal.ArticleNews) internal.Cursor {
+	key := internal.FormatPublishDate(article.PublishDate)
+	if sort.Field == internal.SortByCreateAt {
+		key = strconv.FormatInt(article.CreateAt.Unix(), 10)
+	}
+
+	return internal.Cursor{
+		Sort: sort.String(),
+		Key:  key,
+		ID:   article.NewsID,
+	}
+}
+
+// tsQuery matches the words starting with any of the terms.
+func tsQuery(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		term = strings.NewReplacer(`\`, `\\`, `'`, `''`).Replace(term)
+		quoted[i] = "'" + term + "':*"
+	}
+
+	return strings.Join(quoted, " | ")
+}
+
+// rankWeights are the weights of the D, C, B and A labels of the search
+// column, in the order expected by ts_rank.
+func rankWeights() pq.Float64Array {
+	fields := search.Fields(internal.ArticleNews{})
+	top := float64(search.Weights[fields[0].Name])
+	weights := make(pq.Float64Array, len(fields))
+	for i, field := range fields {
+		weights[len(fields)-1-i] = float64(search.Weights[field.Name]) / top
+	}
+
+	return weights
+}
+
+func placeholders(from, n int) string {
+	values := make([]string, n)
+	for i := range values {
+		values[i] = "$" + strconv.Itoa(from+i)
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// excluded sets the columns to the values of the row that conflicted.
+func excluded(names []string) string {
+	sets := make([]string, len(names))
+	for i, name := range names {
+		sets[i] = name + " = EXCLUDED." + name
+	}
+
+	return strings.Join(sets, ", ")
+}
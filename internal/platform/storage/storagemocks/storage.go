@@ -14,6 +14,43 @@ type Storage struct {
 	mock.Mock
 }
 
+// Archive provides a mock function with given fields: ctx, query
+func (_m *Storage) Archive(ctx context.Context, query internal.ArchiveQuery) ([]internal.ArticleNews, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []internal.ArticleNews
+	if rf, ok := ret.Get(0).(func(context.Context, internal.ArchiveQuery) []internal.ArticleNews); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.ArticleNews)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, internal.ArchiveQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteDeadLetter provides a mock function with given fields: ctx, provider, articleID
+func (_m *Storage) DeleteDeadLetter(ctx context.Context, provider string, articleID string) error {
+	ret := _m.Called(ctx, provider, articleID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, provider, articleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetArticleByID provides a mock function with given fields: ctx, ID
 func (_m *Storage) GetArticleByID(ctx context.Context, ID string) (*internal.ArticleNews, error) {
 	ret := _m.Called(ctx, ID)
@@ -60,6 +97,98 @@ func (_m *Storage) GetArticles(ctx context.Context) ([]internal.ArticleNews, err
 	return r0, r1
 }
 
+// GetArticlesPage provides a mock function with given fields: ctx, query
+func (_m *Storage) GetArticlesPage(ctx context.Context, query internal.ArticleQuery) (*internal.ArticlePage, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 *internal.ArticlePage
+	if rf, ok := ret.Get(0).(func(context.Context, internal.ArticleQuery) *internal.ArticlePage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*internal.ArticlePage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, internal.ArticleQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeadLetter provides a mock function with given fields: ctx, provider, articleID
+func (_m *Storage) GetDeadLetter(ctx context.Context, provider string, articleID string) (*internal.DeadLetter, error) {
+	ret := _m.Called(ctx, provider, articleID)
+
+	var r0 *internal.DeadLetter
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *internal.DeadLetter); ok {
+		r0 = rf(ctx, provider, articleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*internal.DeadLetter)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, provider, articleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeadLetters provides a mock function with given fields: ctx
+func (_m *Storage) GetDeadLetters(ctx context.Context) ([]internal.DeadLetter, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []internal.DeadLetter
+	if rf, ok := ret.Get(0).(func(context.Context) []internal.DeadLetter); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.DeadLetter)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRevisions provides a mock function with given fields: ctx, ID
+func (_m *Storage) GetRevisions(ctx context.Context, ID string) ([]internal.Revision, error) {
+	ret := _m.Called(ctx, ID)
+
+	var r0 []internal.Revision
+	if rf, ok := ret.Get(0).(func(context.Context, string) []internal.Revision); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.Revision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Save provides a mock function with given fields: ctx, news
 func (_m *Storage) Save(ctx context.Context, news internal.ArticleNews) error {
 	ret := _m.Called(ctx, news)
@@ -73,3 +202,82 @@ func (_m *Storage) Save(ctx context.Context, news internal.ArticleNews) error {
 
 	return r0
 }
+
+// SaveDeadLetter provides a mock function with given fields: ctx, deadLetter
+func (_m *Storage) SaveDeadLetter(ctx context.Context, deadLetter internal.DeadLetter) error {
+	ret := _m.Called(ctx, deadLetter)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.DeadLetter) error); ok {
+		r0 = rf(ctx, deadLetter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, query
+func (_m *Storage) Search(ctx context.Context, query internal.SearchQuery) ([]internal.SearchResult, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []internal.SearchResult
+	if rf, ok := ret.Get(0).(func(context.Context, internal.SearchQuery) []internal.SearchResult); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.SearchResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, internal.SearchQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Upsert provides a mock function with given fields: ctx, news
+func (_m *Storage) Upsert(ctx context.Context, news internal.ArticleNews) (internal.UpsertResult, error) {
+	ret := _m.Called(ctx, news)
+
+	var r0 internal.UpsertResult
+	if rf, ok := ret.Get(0).(func(context.Context, internal.ArticleNews) internal.UpsertResult); ok {
+		r0 = rf(ctx, news)
+	} else {
+		r0 = ret.Get(0).(internal.UpsertResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, internal.ArticleNews) error); ok {
+		r1 = rf(ctx, news)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Withdraw provides a mock function with given fields: ctx, withdrawal
+func (_m *Storage) Withdraw(ctx context.Context, withdrawal internal.Withdrawal) (internal.WithdrawResult, error) {
+	ret := _m.Called(ctx, withdrawal)
+
+	var r0 internal.WithdrawResult
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Withdrawal) internal.WithdrawResult); ok {
+		r0 = rf(ctx, withdrawal)
+	} else {
+		r0 = ret.Get(0).(internal.WithdrawResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Withdrawal) error); ok {
+		r1 = rf(ctx, withdrawal)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,18 @@
+package storagetest
+
+import (
+	"context"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+)
+
+// FailingStorage is a Storage whose Upsert fails with Err, to test how a
+// store failure is handled. The other methods are the ones of Storage.
+type FailingStorage struct {
+	internal.Storage
+	Err error
+}
+
+func (s *FailingStorage) Upsert(_ context.Context, _ internal.ArticleNews) (internal.UpsertResult, error) {
+	return internal.UpsertUnchanged, s.Err
+}
@@ -0,0 +1,574 @@
+// Package storagetest is the conformance suite of the implementations of
+// internal.Storage, every backend runs it from its own tests.
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+)
+
+// NewStorage returns an empty storage for a test.
+type NewStorage func(t *testing.T) internal.Storage
+
+// Run runs the conformance suite against the storages returned by
+// newStorage, every test case starts from an empty storage.
+func Run(t *testing.T, newStorage NewStorage) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, newStorage NewStorage)
+	}{
+		{name: "save", test: testSave},
+		{name: "get article by id", test: testGetArticleByID},
+		{name: "get articles", test: testGetArticles},
+		{name: "upsert", test: testUpsert},
+		{name: "articles page", test: testArticlesPage},
+		{name: "search", test: testSearch},
+		{name: "withdraw", test: testWithdraw},
+		{name: "archive", test: testArchive},
+		{name: "upsert archived", test: testUpsertArchived},
+		{name: "dead letters", test: testDeadLetters},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStorage)
+		})
+	}
+}
+
+func testSave(t *testing.T, newStorage NewStorage) {
+	article := Article("641838", "2022-06-15 08:00:00")
+	renamed := article
+	renamed.Title = "Pontus Jansson explains"
+	withdrawn := Article("641839", "2022-06-15 08:00:00")
+	withdrawn.IsPublished = false
+	withdrawn.DeletedAt = time.Date(2022, 6, 17, 8, 0, 0, 0, time.UTC)
+	empty := Article("641840", "2022-06-15 08:00:00")
+	empty.GalleryImageURLs = []string{}
+	empty.Taxonomies = []string{}
+
+	tests := []struct {
+		name   string
+		stored []internal.ArticleNews
+		save   internal.ArticleNews
+		// want is the article read back, save when nil
+		want *internal.ArticleNews
+		// wantAll is every article stored after the save
+		wantAll []internal.ArticleNews
+	}{
+		{
+			name:    "new article",
+			save:    article,
+			wantAll: []internal.ArticleNews{article},
+		},
+		{
+			name:    "replaces the article with the same id",
+			stored:  []internal.ArticleNews{article},
+			save:    renamed,
+			wantAll: []internal.ArticleNews{renamed},
+		},
+		{
+			name:    "keeps the other articles",
+			stored:  []internal.ArticleNews{withdrawn},
+			save:    article,
+			wantAll: []internal.ArticleNews{withdrawn, article},
+		},
+		{
+			name:    "withdrawn article",
+			save:    withdrawn,
+			wantAll: []internal.ArticleNews{withdrawn},
+		},
+		{
+			name:    "only required fields",
+			save:    minimalArticle("641838"),
+			wantAll: []internal.ArticleNews{minimalArticle("641838")},
+		},
+		{
+			name:    "empty lists are read back as nil",
+			save:    empty,
+			want:    withoutLists(empty),
+			wantAll: []internal.ArticleNews{*withoutLists(empty)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newStorage(t)
+			ctx := context.Background()
+			for _, article := range tt.stored {
+				require.NoError(t, repo.Save(ctx, article))
+			}
+
+			require.NoError(t, repo.Save(ctx, tt.save))
+
+			want := tt.want
+			if want == nil {
+				want = &tt.save
+			}
+
+			got, err := repo.GetArticleByID(ctx, tt.save.NewsID)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+
+			all, err := repo.GetArticles(ctx)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.wantAll, all)
+		})
+	}
+}
+
+func testGetArticleByID(t *testing.T, newStorage NewStorage) {
+	article := Article("641838", "2022-06-15 08:00:00")
+	other := Article("6418", "2022-06-14 08:00:00")
+	other.ClubName = "Arsenal"
+	hidden := Article("641839", "2022-06-15 08:00:00")
+	hidden.IsPublished = false
+	hidden.DeletedAt = time.Date(2022, 6, 17, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		stored  []internal.ArticleNews
+		id      string
+		want    *internal.ArticleNews
+		wantErr error
+	}{
+		{
+			name:   "stored article",
+			stored: []internal.ArticleNews{other, article},
+			id:     "641838",
+			want:   &article,
+		},
+		{
+			// the service hides it from the readers that are not admins
+			name:   "hidden article",
+			stored: []internal.ArticleNews{article, hidden},
+			id:     "641839",
+			want:   &hidden,
+		},
+		{
+			name:    "empty storage",
+			id:      "641838",
+			wantErr: internal.ErrArticleNotFound,
+		},
+		{
+			name:    "missing article",
+			stored:  []internal.ArticleNews{other, article},
+			id:      "641839",
+			wantErr: internal.ErrArticleNotFound,
+		},
+		{
+			name:    "ids are matched whole",
+			stored:  []internal.ArticleNews{other, article},
+			id:      "64183",
+			wantErr: internal.ErrArticleNotFound,
+		},
+		{
+			name:    "empty id",
+			stored:  []internal.ArticleNews{other, article},
+			wantErr: internal.ErrArticleNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newStorage(t)
+			ctx := context.Background()
+			for _, article := range tt.stored {
+				require.NoError(t, repo.Save(ctx, article))
+			}
+
+			got, err := repo.GetArticleByID(ctx, tt.id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func testGetArticles(t *testing.T, newStorage NewStorage) {
+	unpublished := Article("2", "2022-06-15 08:00:00")
+	unpublished.IsPublished = false
+	deleted := Article("3", "2022-06-16 08:00:00")
+	deleted.DeletedAt = time.Date(2022, 6, 17, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		stored []internal.ArticleNews
+		want   []internal.ArticleNews
+	}{
+		{
+			name: "empty storage",
+			want: []internal.ArticleNews{},
+		},
+		{
+			name:   "every article",
+			stored: []internal.ArticleNews{Article("1", "2022-06-14 08:00:00"), unpublished, deleted},
+			want:   []internal.ArticleNews{Article("1", "2022-06-14 08:00:00"), unpublished, deleted},
+		},
+		{
+			name: "saved twice",
+			stored: []internal.ArticleNews{
+				Article("1", "2022-06-14 08:00:00"),
+				Article("1", "2022-06-14 08:00:00"),
+			},
+			want: []internal.ArticleNews{Article("1", "2022-06-14 08:00:00")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newStorage(t)
+			ctx := context.Background()
+			for _, article := range tt.stored {
+				require.NoError(t, repo.Save(ctx, article))
+			}
+
+			got, err := repo.GetArticles(ctx)
+			require.NoError(t, err)
+			// an empty storage returns an empty slice, not nil
+			require.NotNil(t, got)
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func testUpsert(t *testing.T, newStorage NewStorage) {
+	repo := newStorage(t)
+	ctx := context.Background()
+	article := Article("641838", "2022-06-15 08:00:00")
+
+	result, err := repo.Upsert(ctx, article)
+	require.NoError(t, err)
+	assert.Equal(t, internal.UpsertInserted, result)
+
+	result, err = repo.Upsert(ctx, article)
+	require.NoError(t, err)
+	assert.Equal(t, internal.UpsertUnchanged, result)
+
+	updated := article
+	updated.Title = "Pontus Jansson explains"
+	updated.LastUpdateDate = "2022-06-16 10:00:00"
+	updated.CreateAt = article.CreateAt.Add(time.Hour)
+	result, err = repo.Upsert(ctx, updated)
+	require.NoError(t, err)
+	assert.Equal(t, internal.UpsertUpdated, result)
+
+	got, err := repo.GetArticleByID(ctx, article.NewsID)
+	require.NoError(t, err)
+	assert.Equal(t, updated.Title, got.Title)
+	assert.Equal(t, article.CreateAt, got.CreateAt)
+
+	revisions, err := repo.GetRevisions(ctx, article.NewsID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, 1, revisions[0].Number)
+	assert.Equal(t, article.Title, revisions[0].Article.Title)
+
+	revisions, err = repo.GetRevisions(ctx, "missing")
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+}
+
+func testArticlesPage(t *testing.T, newStorage NewStorage) {
+	repo := newStorage(t)
+	ctx := context.Background()
+	for _, article := range []internal.ArticleNews{
+		Article("1", "2022-06-14 08:00:00"),
+		Article("2", "2022-06-15 08:00:00"),
+		Article("3", "2022-06-14 08:00:00"),
+	} {
+		require.NoError(t, repo.Save(ctx, article))
+	}
+
+	query := internal.ArticleQuery{Limit: 2, Sort: internal.DefaultSort()}
+	got, err := repo.GetArticlesPage(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "3"}, IDs(got.Articles))
+	require.NotEmpty(t, got.Next)
+
+	query.Cursor = got.Next
+	got, err = repo.GetArticlesPage(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, IDs(got.Articles))
+	assert.Empty(t, got.Next)
+
+	from := time.Date(2022, 6, 15, 0, 0, 0, 0, time.UTC)
+	query = internal.ArticleQuery{
+		Limit:  10,
+		Sort:   internal.Sort{Field: internal.SortByPublishDate, Ascending: true},
+		Filter: internal.ArticleFilter{ClubName: "Brentford", Taxonomy: "Interviews", From: from},
+	}
+	got, err = repo.GetArticlesPage(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, IDs(got.Articles))
+}
+
+func testSearch(t *testing.T, newStorage NewStorage) {
+	repo := newStorage(t)
+	ctx := context.Background()
+	title := Article("1", "2022-06-14 08:00:00")
+	title.Title = "Toney called up"
+	body := Article("2", "2022-06-15 08:00:00")
+	body.BodyText = "Toney and Mbeumo"
+	unpublished := Article("4", "2022-06-15 08:00:00")
+	unpublished.Title = "Toney signs"
+	unpublished.IsPublished = false
+	deleted := Article("5", "2022-06-15 08:00:00")
+	deleted.Title = "Toney injured"
+	deleted.DeletedAt = time.Date(2022, 6, 17, 8, 0, 0, 0, time.UTC)
+	stored := []internal.ArticleNews{body, title, Article("3", "2022-06-15 08:00:00"), unpublished, deleted}
+	for _, article := range stored {
+		require.NoError(t, repo.Save(ctx, article))
+	}
+
+	// deleted and unpublished articles are never found
+
+	got, err := repo.Search(ctx, internal.SearchQuery{Text: "toney", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "1", got[0].Article.NewsID)
+	assert.Equal(t, "2", got[1].Article.NewsID)
+	assert.Greater(t, got[0].Score, got[1].Score)
+	assert.Equal(t, []string{"<em>Toney</em> called up"}, got[0].Highlights)
+
+	_, err = repo.Search(ctx, internal.SearchQuery{Text: " "})
+	assert.ErrorIs(t, err, internal.ErrInvalidSearch)
+}
+
+func testWithdraw(t *testing.T, newStorage NewStorage) {
+	repo := newStorage(t)
+	ctx := context.Background()
+	for _, article := range []internal.ArticleNews{
+		Article("1", "2022-06-10 08:00:00"),
+		Article("2", "2022-06-14 08:00:00"),
+		Article("3", "2022-06-15 08:00:00"),
+		Article("4", "2022-06-16 08:00:00"),
+	} {
+		article.Provider = "brentford"
+		require.NoError(t, repo.Save(ctx, article))
+	}
+
+	at := time.Date(2022, 6, 17, 8, 0, 0, 0, time.UTC)
+	result, err := repo.Withdraw(ctx, internal.Withdrawal{
+		Provider:    "brentford",
+		Listed:      []string{"2", "4"},
+		Unpublished: []string{"4"},
+		Since:       time.Date(2022, 6, 14, 8, 0, 0, 0, time.UTC),
+		At:          at,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, internal.WithdrawResult{Deleted: 1, Unpublished: 1}, result)
+
+	deleted := false
+	query := internal.ArticleQuery{Limit: 10, Sort: internal.DefaultSort(), Filter: internal.ArticleFilter{Deleted: &deleted}}
+	got, err := repo.GetArticlesPage(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4", "2", "1"}, IDs(got.Articles))
+
+	article, err := repo.GetArticleByID(ctx, "3")
+	require.NoError(t, err)
+	assert.Equal(t, at, article.DeletedAt)
+
+	article, err = repo.GetArticleByID(ctx, "4")
+	require.NoError(t, err)
+	assert.False(t, article.IsPublished)
+}
+
+func testArchive(t *testing.T, newStorage NewStorage) {
+	repo := newStorage(t)
+	ctx := context.Background()
+	old := Article("1", "2021-06-14 08:00:00")
+	other := Article("2", "2021-06-14 08:00:00")
+	other.ClubName = "Arsenal"
+	for _, article := range []internal.ArticleNews{old, other, Article("3", "2022-06-15 08:00:00")} {
+		require.NoError(t, repo.Save(ctx, article))
+	}
+
+	at := time.Date(2022, 6, 17, 3, 0, 0, 0, time.UTC)
+	archived, err := repo.Archive(ctx, internal.ArchiveQuery{
+		ExceptClubs: []string{"Arsenal"},
+		Before:      time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		At:          at,
+	})
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, "1", archived[0].NewsID)
+	assert.Equal(t, at, archived[0].ArchivedAt)
+
+	_, err = repo.GetArticleByID(ctx, "1")
+	assert.ErrorIs(t, err, internal.ErrArticleNotFound)
+
+	all, err := repo.GetArticles(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"2", "3"}, IDs(all))
+
+	query := internal.ArticleQuery{Limit: 10, Sort: internal.DefaultSort()}
+	got, err := repo.GetArticlesPage(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3", "2"}, IDs(got.Articles))
+
+	query.IncludeArchived = true
+	got, err = repo.GetArticlesPage(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3", "2", "1"}, IDs(got.Articles))
+}
+
+func testUpsertArchived(t *testing.T, newStorage NewStorage) {
+	repo := newStorage(t)
+	ctx := context.Background()
+	article := Article("1", "2021-06-14 08:00:00")
+	require.NoError(t, repo.Save(ctx, article))
+
+	at := time.Date(2022, 6, 17, 3, 0, 0, 0, time.UTC)
+	_, err := repo.Archive(ctx, internal.ArchiveQuery{Before: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), At: at})
+	require.NoError(t, err)
+
+	// the provider still lists the archived article
+	result, err := repo.Upsert(ctx, article)
+	require.NoError(t, err)
+	assert.Equal(t, internal.UpsertUnchanged, result)
+
+	updated := article
+	updated.Title = "Pontus Jansson explains"
+	updated.LastUpdateDate = "2022-06-18 10:00:00"
+	result, err = repo.Upsert(ctx, updated)
+	require.NoError(t, err)
+	assert.Equal(t, internal.UpsertUpdated, result)
+
+	all, err := repo.GetArticles(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+
+	got, err := repo.GetArticlesPage(ctx, internal.ArticleQuery{Limit: 10, Sort: internal.DefaultSort(), IncludeArchived: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, IDs(got.Articles))
+	assert.Equal(t, updated.Title, got.Articles[0].Title)
+	assert.Equal(t, at, got.Articles[0].ArchivedAt)
+	assert.Equal(t, article.CreateAt, got.Articles[0].CreateAt)
+
+	revisions, err := repo.GetRevisions(ctx, article.NewsID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, article.Title, revisions[0].Article.Title)
+}
+
+func testDeadLetters(t *testing.T, newStorage NewStorage) {
+	repo := newStorage(t)
+	ctx := context.Background()
+	got, err := repo.GetDeadLetters(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []internal.DeadLetter{}, got)
+
+	_, err = repo.GetDeadLetter(ctx, "brentford", "641838")
+	assert.ErrorIs(t, err, internal.ErrDeadLetterNotFound)
+	assert.ErrorIs(t, repo.DeleteDeadLetter(ctx, "brentford", "641838"), internal.ErrDeadLetterNotFound)
+
+	failedAt := time.Date(2022, 6, 15, 8, 0, 0, 0, time.UTC)
+	first := internal.DeadLetter{
+		ArticleID:   "641838",
+		Provider:    "brentford",
+		ArticleURL:  "https://www.brentfordfc.com/api/incrowd/getnewsarticleinformation?id=%s",
+		IsPublished: true,
+		Stage:       "fetch",
+		Error:       "resource not found",
+		Attempts:    3,
+		FailedAt:    failedAt.Add(time.Hour),
+	}
+	second := first
+	second.ArticleID = "641839"
+	second.FailedAt = failedAt
+	// the same article ID at another provider is another dead letter
+	other := first
+	other.Provider = "arsenal"
+	other.ArticleURL = "https://www.arsenal.com/api/incrowd/getnewsarticleinformation?id=%s"
+	for _, deadLetter := range []internal.DeadLetter{first, second, other} {
+		require.NoError(t, repo.SaveDeadLetter(ctx, deadLetter))
+	}
+
+	// a new failure of the same article replaces its dead letter
+	first.Stage = "parse"
+	first.Attempts = 1
+	require.NoError(t, repo.SaveDeadLetter(ctx, first))
+
+	got, err = repo.GetDeadLetters(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []internal.DeadLetter{second, other, first}, got)
+
+	deadLetter, err := repo.GetDeadLetter(ctx, first.Provider, first.ArticleID)
+	require.NoError(t, err)
+	assert.Equal(t, first, *deadLetter)
+
+	deadLetter, err = repo.GetDeadLetter(ctx, other.Provider, other.ArticleID)
+	require.NoError(t, err)
+	assert.Equal(t, other, *deadLetter)
+
+	require.NoError(t, repo.DeleteDeadLetter(ctx, second.Provider, second.ArticleID))
+	_, err = repo.GetDeadLetter(ctx, second.Provider, second.ArticleID)
+	assert.ErrorIs(t, err, internal.ErrDeadLetterNotFound)
+
+	require.NoError(t, repo.DeleteDeadLetter(ctx, other.Provider, other.ArticleID))
+	got, err = repo.GetDeadLetters(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []internal.DeadLetter{first}, got)
+}
+
+// Article returns a published article of Brentford with every field set,
+// publishDate is in internal.PublishDateLayout.
+func Article(id, publishDate string) internal.ArticleNews {
+	date, _ := internal.ParsePublishDate(publishDate)
+
+	return internal.ArticleNews{
+		NewsID:            id,
+		ClubName:          "Brentford",
+		ClubWebsiteURL:    "https://www.brentfordfc.com",
+		ArticleURL:        "https://www.brentfordfc.com/news/" + id,
+		Title:             "Pontus explains",
+		Subtitle:          "Defender",
+		BodyText:          "Pontus Jansson explains his decision",
+		Body:              internal.TextBody("Pontus Jansson explains his decision"),
+		GalleryImageURLs:  []string{"https://www.brentfordfc.com/a.jpg"},
+		Taxonomies:        []string{"Players", "Interviews"},
+		TeaserText:        "Jansson",
+		ThumbnailImageURL: "https://www.brentfordfc.com/thumb.jpg",
+		PublishDate:       date,
+		LastUpdateDate:    publishDate,
+		IsPublished:       true,
+		CreateAt:          date.Add(time.Minute),
+	}
+}
+
+// minimalArticle returns an article with only the fields the providers
+// always set.
+func minimalArticle(id string) internal.ArticleNews {
+	return internal.ArticleNews{
+		NewsID:   id,
+		ClubName: "Brentford",
+		Title:    "Pontus explains",
+		CreateAt: time.Date(2022, 6, 15, 8, 1, 0, 0, time.UTC),
+	}
+}
+
+// withoutLists returns the article with nil lists of images and taxonomies.
+func withoutLists(article internal.ArticleNews) *internal.ArticleNews {
+	article.GalleryImageURLs = nil
+	article.Taxonomies = nil
+
+	return &article
+}
+
+// IDs returns the NewsID of the articles.
+func IDs(articles []internal.ArticleNews) []string {
+	ids := make([]string, 0, len(articles))
+	for _, article := range articles {
+		ids = append(ids, article.NewsID)
+	}
+
+	return ids
+}
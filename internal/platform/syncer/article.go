@@ -0,0 +1,83 @@
+package syncer
+
+import (
+	"time"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+)
+
+// Article is the wire format of a complete article in the batches the
+// syncers publish, decoupled from internal.ArticleNews so that changes
+// of the domain do not change the messages of the queue.
+type Article struct {
+	ID                string         `json:"id"`
+	ClubName          string         `json:"club_name"`
+	ClubWebsiteURL    string         `json:"club_website_url,omitempty"`
+	ArticleURL        string         `json:"article_url,omitempty"`
+	Title             string         `json:"title"`
+	Subtitle          string         `json:"subtitle,omitempty"`
+	BodyText          string         `json:"body_text,omitempty"`
+	Body              *internal.Body `json:"body,omitempty"`
+	GalleryImageURLs  []string       `json:"gallery_image_urls,omitempty"`
+	VideoURL          string         `json:"video_url,omitempty"`
+	Taxonomies        []string       `json:"taxonomies,omitempty"`
+	TeaserText        string         `json:"teaser_text,omitempty"`
+	ThumbnailImageURL string         `json:"thumbnail_image_url,omitempty"`
+	PublishDate       time.Time      `json:"publish_date"`
+	LastUpdateDate    string         `json:"last_update_date,omitempty"`
+	IsPublished       bool           `json:"is_published"`
+}
+
+// NewArticle returns the wire format of the article. The provider is
+// the one of the batch, and the storage fields are not published.
+func NewArticle(article internal.ArticleNews) Article {
+	a := Article{
+		ID:                article.NewsID,
+		ClubName:          article.ClubName,
+		ClubWebsiteURL:    article.ClubWebsiteURL,
+		ArticleURL:        article.ArticleURL,
+		Title:             article.Title,
+		Subtitle:          article.Subtitle,
+		BodyText:          article.BodyText,
+		GalleryImageURLs:  article.GalleryImageURLs,
+		VideoURL:          article.VideoURL,
+		Taxonomies:        article.Taxonomies,
+		TeaserText:        article.TeaserText,
+		ThumbnailImageURL: article.ThumbnailImageURL,
+		PublishDate:       article.PublishDate,
+		LastUpdateDate:    article.LastUpdateDate,
+		IsPublished:       article.IsPublished,
+	}
+	if !article.Body.IsEmpty() {
+		body := article.Body
+		a.Body = &body
+	}
+
+	return a
+}
+
+// ToArticle returns the article of the wire format.
+func (a Article) ToArticle() internal.ArticleNews {
+	article := internal.ArticleNews{
+		NewsID:            a.ID,
+		ClubName:          a.ClubName,
+		ClubWebsiteURL:    a.ClubWebsiteURL,
+		ArticleURL:        a.ArticleURL,
+		Title:             a.Title,
+		Subtitle:          a.Subtitle,
+		BodyText:          a.BodyText,
+		GalleryImageURLs:  a.GalleryImageURLs,
+		VideoURL:          a.VideoURL,
+		Taxonomies:        a.Taxonomies,
+		TeaserText:        a.TeaserText,
+		ThumbnailImageURL: a.ThumbnailImageURL,
+		PublishDate:       a.PublishDate,
+		LastUpdateDate:    a.LastUpdateDate,
+		IsPublished:       a.IsPublished,
+	}
+	if a.Body != nil {
+		article.Body = *a.Body
+	}
+
+	return article
+}
@@ -0,0 +1,33 @@
+package syncer
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+)
+
+func TestArticle(t *testing.T) {
+	article := internal.ArticleNews{
+		NewsID:      "arsenal-1",
+		ClubName:    "Arsenal",
+		Title:       "Derby preview",
+		Body:        internal.TextBody("Arteta previews the north London derby"),
+		Taxonomies:  []string{"First team"},
+		PublishDate: time.Date(2022, 6, 15, 8, 0, 0, 0, time.UTC),
+		IsPublished: true,
+	}
+
+	data, err := json.Marshal(NewArticle(article))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"id":"arsenal-1"`)
+	assert.Contains(t, string(data), `"publish_date":"2022-06-15T08:00:00Z"`)
+
+	var got Article
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, article, got.ToArticle())
+}
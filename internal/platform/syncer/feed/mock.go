@@ -0,0 +1,71 @@
+package feed
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/patriciabonaldy/sports-news/internal/platform/genericClient"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+)
+
+type mockClient struct {
+	file      string
+	wantError bool
+}
+
+func (m mockClient) Delete(_ context.Context, _ string, _ ...genericClient.Header) error {
+	if m.wantError {
+		return errors.New("unknown error")
+	}
+
+	return nil
+}
+
+func (m mockClient) Get(_ context.Context, _ string) (resp *http.Response, err error) {
+	if m.wantError {
+		return nil, errors.New("unknown error")
+	}
+
+	data, err := os.ReadFile(filepath.Join("testdata", m.file))
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Body:       io.NopCloser(bytes.NewReader(data)),
+	}, nil
+}
+
+func (m mockClient) Post(_ context.Context, _ string, _ []byte, _ ...genericClient.Header) (resp *http.Response, err error) {
+	return nil, errors.New("not implemented")
+}
+
+var _ genericClient.Client = &mockClient{}
+
+type mockLog struct{}
+
+func (m mockLog) Error(args ...interface{}) {
+}
+
+func (m mockLog) ErrorTrace(err error) {
+}
+
+func (m mockLog) Errorf(format string, args ...interface{}) {
+}
+
+func (m mockLog) Info(args ...interface{}) {
+}
+
+func (m mockLog) Infof(format string, args ...interface{}) {
+}
+
+var _ logger.Logger = &mockLog{}
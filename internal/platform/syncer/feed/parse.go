@@ -0,0 +1,202 @@
+package feed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/richtext"
+)
+
+// ErrUnknownFormat is returned for documents that are neither RSS 2.0 nor Atom.
+var ErrUnknownFormat = errors.New("feed is neither rss nor atom")
+
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	time.RFC3339,
+}
+
+// Source describes who publishes a feed. ClubName defaults to the title
+// of the feed.
+type Source struct {
+	Provider string
+	ClubName string
+}
+
+// Parse maps the items of a RSS 2.0 document or the entries of an Atom
+// document onto articles. IDs are derived from the guid (or link) of
+// every item and the provider, so they are stable across syncs.
+func Parse(data []byte, source Source) ([]internal.ArticleNews, error) {
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case root.XMLName.Local == "rss":
+		return parseRSS(data, source)
+	case root.XMLName.Local == "feed" && root.XMLName.Space == "http://www.w3.org/2005/Atom":
+		return parseAtom(data, source)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+func parseRSS(data []byte, source Source) ([]internal.ArticleNews, error) {
+	var doc rss
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	clubName := source.ClubName
+	if clubName == "" {
+		clubName = strings.TrimSpace(doc.Channel.Title)
+	}
+
+	articles := make([]internal.ArticleNews, 0, len(doc.Channel.Items))
+	for _, item := range doc.Channel.Items {
+		guid := firstNonEmpty(item.GUID, item.Link, item.Title)
+		body := richtext.Parse(firstNonEmpty(item.Content, item.Description))
+		publishDate := parseDate(item.PubDate)
+		thumbnail := ""
+		if item.Enclosure.URL != "" && (item.Enclosure.Type == "" || strings.HasPrefix(item.Enclosure.Type, "image/")) {
+			thumbnail = item.Enclosure.URL
+		}
+
+		articles = append(articles, internal.ArticleNews{
+			NewsID:            newsID(source.Provider, guid),
+			ClubName:          clubName,
+			ClubWebsiteURL:    strings.TrimSpace(doc.Channel.Link),
+			ArticleURL:        strings.TrimSpace(item.Link),
+			Title:             strings.TrimSpace(item.Title),
+			BodyText:          body.Text(),
+			Body:              body,
+			Taxonomies:        categories(item.Categories),
+			TeaserText:        richtext.Text(item.Description),
+			ThumbnailImageURL: thumbnail,
+			PublishDate:       publishDate,
+			LastUpdateDate:    internal.FormatPublishDate(publishDate),
+			IsPublished:       true,
+			CreateAt:          time.Now(),
+		})
+	}
+
+	return articles, nil
+}
+
+func parseAtom(data []byte, source Source) ([]internal.ArticleNews, error) {
+	var doc atom
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	clubName := source.ClubName
+	if clubName == "" {
+		clubName = strings.TrimSpace(doc.Title)
+	}
+
+	articles := make([]internal.ArticleNews, 0, len(doc.Entries))
+	for _, entry := range doc.Entries {
+		link := alternateLink(entry.Links)
+		terms := make([]string, 0, len(entry.Categories))
+		for _, c := range entry.Categories {
+			terms = append(terms, c.Term)
+		}
+
+		lastUpdateDate := parseDate(entry.Updated)
+		publishDate := parseDate(entry.Published)
+		if publishDate.IsZero() {
+			publishDate = lastUpdateDate
+		}
+
+		body := richtext.Parse(firstNonEmpty(entry.Content, entry.Summary))
+		articles = append(articles, internal.ArticleNews{
+			NewsID:            newsID(source.Provider, firstNonEmpty(entry.ID, link, entry.Title)),
+			ClubName:          clubName,
+			ClubWebsiteURL:    alternateLink(doc.Links),
+			ArticleURL:        link,
+			Title:             strings.TrimSpace(entry.Title),
+			BodyText:          body.Text(),
+			Body:              body,
+			Taxonomies:        categories(terms),
+			TeaserText:        richtext.Text(entry.Summary),
+			ThumbnailImageURL: enclosure(entry.Links),
+			PublishDate:       publishDate,
+			LastUpdateDate:    internal.FormatPublishDate(lastUpdateDate),
+			IsPublished:       true,
+			CreateAt:          time.Now(),
+		})
+	}
+
+	return articles, nil
+}
+
+// newsID prefixes a hash of the guid with the provider, guids are URLs
+// most of the time and they are only unique within a feed.
+func newsID(provider, guid string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(guid)))
+	return provider + "-" + hex.EncodeToString(sum[:8])
+}
+
+// parseDate parses the RFC 822 dates of RSS and the RFC 3339 dates of
+// Atom, in UTC. Invalid dates are the zero time.
+func parseDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+
+	return time.Time{}
+}
+
+func alternateLink(links []atomLink) string {
+	for _, l := range links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return strings.TrimSpace(l.Href)
+		}
+	}
+
+	return ""
+}
+
+func enclosure(links []atomLink) string {
+	for _, l := range links {
+		if l.Rel == "enclosure" && (l.Type == "" || strings.HasPrefix(l.Type, "image/")) {
+			return strings.TrimSpace(l.Href)
+		}
+	}
+
+	return ""
+}
+
+func categories(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+
+	return ""
+}
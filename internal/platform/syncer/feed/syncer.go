@@ -1,9 +1,8 @@
-package brentfordFC
+package feed
 
 import (
 	"context"
 	"encoding/json"
-	"encoding/xml"
 	"io"
 
 	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
@@ -14,21 +13,41 @@ import (
 	"github.com/patriciabonaldy/sports-news/internal/platform/syncer"
 )
 
+// SyncerNews synchronizes a RSS 2.0 or Atom feed. Feed items already
+// carry the whole article, so the batches it publishes do not need
+// a detail URL.
 type SyncerNews struct {
 	client   genericClient.Client
 	producer pubsub.Producer
 	log      logger.Logger
+	source   Source
 	url      string
 }
 
+// newsBatch is the message published for the pipeline.
+type newsBatch struct {
+	Provider string           `json:"provider"`
+	Articles []syncer.Article `json:"articles"`
+}
+
 var _ syncer.Syncer = &SyncerNews{}
 
-func NewSyncer(client genericClient.Client, producer pubsub.Producer, log logger.Logger, config *config.Config) syncer.Syncer {
+var _ syncer.Factory = NewSyncer
+
+// NewSyncer returns the syncer of a feed provider, the feed is read
+// from list_url or else from base_url.
+func NewSyncer(provider config.Provider, client genericClient.Client, producer pubsub.Producer, log logger.Logger) syncer.Syncer {
+	url := provider.ListURL
+	if url == "" {
+		url = provider.BaseURL
+	}
+
 	return &SyncerNews{
 		client:   client,
 		producer: producer,
 		log:      log,
-		url:      config.SportsNewsURL,
+		source:   Source{Provider: provider.Name, ClubName: provider.ClubName},
+		url:      url,
 	}
 }
 
@@ -40,20 +59,24 @@ func (s *SyncerNews) Sync(ctx context.Context) error {
 	}
 
 	defer resp.Body.Close()
-	var newListInf NewListInformation
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
 		s.log.Errorf("error read body - sync %s", err)
 		return err
 	}
 
-	err = xml.Unmarshal(data, &newListInf)
+	articles, err := Parse(data, s.source)
 	if err != nil {
 		s.log.Errorf("error unmarshall - sync %s", err)
 		return err
 	}
 
-	m, err := generateMessage(newListInf.NewsletterNewsItems.NewsletterNewsItem)
+	batch := newsBatch{Provider: s.source.Provider, Articles: make([]syncer.Article, 0, len(articles))}
+	for _, article := range articles {
+		batch.Articles = append(batch.Articles, syncer.NewArticle(article))
+	}
+
+	m, err := generateMessage(batch)
 	if err != nil {
 		s.log.Errorf("error generate message - sync %s", err)
 		return err
@@ -68,13 +91,13 @@ func (s *SyncerNews) Sync(ctx context.Context) error {
 	return nil
 }
 
-func generateMessage(articles []NewsletterNewsItem) (*pubsub.Message, error) {
+func generateMessage(batch newsBatch) (*pubsub.Message, error) {
 	message, err := pubsub.NewSystemMessage()
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := json.Marshal(articles)
+	data, err := json.Marshal(batch)
 	if err != nil {
 		return nil, err
 	}
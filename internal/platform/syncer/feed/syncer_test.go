@@ -0,0 +1,195 @@
+package feed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/genericClient"
+	"github.com/patriciabonaldy/sports-news/internal/platform/pubsub"
+	"github.com/patriciabonaldy/sports-news/internal/platform/pubsub/pubsubMock"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		source  Source
+		want    []internal.ArticleNews
+		wantErr error
+	}{
+		{
+			name:   "rss",
+			file:   "rss.xml",
+			source: Source{Provider: "arsenal"},
+			want: []internal.ArticleNews{
+				{
+					NewsID:            newsID("arsenal", "https://www.arsenal.com/news/arteta-previews-derby"),
+					ClubName:          "Arsenal News",
+					ClubWebsiteURL:    "https://www.arsenal.com",
+					ArticleURL:        "https://www.arsenal.com/news/arteta-previews-derby",
+					Title:             "Arteta previews the north London derby",
+					BodyText:          "The boss spoke to the press ahead of Sunday's game.",
+					Body:              paragraph("The boss spoke to the press ahead of Sunday's game."),
+					Taxonomies:        []string{"First Team", "Press conference"},
+					TeaserText:        "The boss on Sunday's game.",
+					ThumbnailImageURL: "https://www.arsenal.com/images/arteta.jpg",
+					PublishDate:       time.Date(2024, 9, 14, 9, 30, 0, 0, time.UTC),
+					LastUpdateDate:    "2024-09-14 09:30:00",
+					IsPublished:       true,
+				},
+				{
+					NewsID:         newsID("arsenal", "https://www.arsenal.com/news/academy-round-up"),
+					ClubName:       "Arsenal News",
+					ClubWebsiteURL: "https://www.arsenal.com",
+					ArticleURL:     "https://www.arsenal.com/news/academy-round-up",
+					Title:          "Academy round-up",
+					BodyText:       "All the results from the weekend.",
+					Body:           paragraph("All the results from the weekend."),
+					TeaserText:     "All the results from the weekend.",
+					PublishDate:    time.Date(2024, 9, 13, 18, 0, 0, 0, time.UTC),
+					LastUpdateDate: "2024-09-13 18:00:00",
+					IsPublished:    true,
+				},
+			},
+		},
+		{
+			name:   "atom",
+			file:   "atom.xml",
+			source: Source{Provider: "premier-league", ClubName: "Premier League"},
+			want: []internal.ArticleNews{
+				{
+					NewsID:            newsID("premier-league", "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a"),
+					ClubName:          "Premier League",
+					ClubWebsiteURL:    "https://www.premierleague.com",
+					ArticleURL:        "https://www.premierleague.com/news/matchweek-4-preview",
+					Title:             "Matchweek 4 preview",
+					BodyText:          "Everything you need to know.",
+					Body:              paragraph("Everything you need to know."),
+					Taxonomies:        []string{"Previews"},
+					TeaserText:        "Everything you need to know ahead of the weekend.",
+					ThumbnailImageURL: "https://www.premierleague.com/images/mw4.png",
+					PublishDate:       time.Date(2024, 9, 13, 7, 0, 0, 0, time.UTC),
+					LastUpdateDate:    "2024-09-14 11:00:00",
+					IsPublished:       true,
+				},
+			},
+		},
+		{
+			name:    "unknown format",
+			file:    "../../incrowd/testdata/brentford_list.xml",
+			wantErr: ErrUnknownFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			require.NoError(t, err)
+
+			got, err := Parse(data, tt.source)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			for i := range got {
+				assert.WithinDuration(t, time.Now(), got[i].CreateAt, time.Minute)
+				got[i].CreateAt = time.Time{}
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func paragraph(text string) internal.Body {
+	return internal.Body{Blocks: []internal.Block{{
+		Type:    internal.BlockParagraph,
+		Inlines: []internal.Inline{{Type: internal.InlineText, Text: text}},
+	}}}
+}
+
+func TestParse_stableIDs(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "rss.xml"))
+	require.NoError(t, err)
+
+	first, err := Parse(data, Source{Provider: "arsenal"})
+	require.NoError(t, err)
+	second, err := Parse(data, Source{Provider: "arsenal"})
+	require.NoError(t, err)
+	other, err := Parse(data, Source{Provider: "gunners"})
+	require.NoError(t, err)
+
+	assert.Equal(t, first[0].NewsID, second[0].NewsID)
+	assert.NotEqual(t, first[0].NewsID, other[0].NewsID)
+	assert.NotEqual(t, first[0].NewsID, first[1].NewsID)
+}
+
+func TestSyncerNews_Sync(t *testing.T) {
+	tests := []struct {
+		name           string
+		client         genericClient.Client
+		fnMockProducer func() pubsub.Producer
+		wantErr        bool
+	}{
+		{
+			name:           "Error in get",
+			client:         &mockClient{wantError: true},
+			fnMockProducer: func() pubsub.Producer { return nil },
+			wantErr:        true,
+		},
+		{
+			name:           "Error parse feed",
+			client:         &mockClient{file: "../../incrowd/testdata/brentford_list.xml"},
+			fnMockProducer: func() pubsub.Producer { return nil },
+			wantErr:        true,
+		},
+		{
+			name:   "Error publish message",
+			client: &mockClient{file: "rss.xml"},
+			fnMockProducer: func() pubsub.Producer {
+				productMock := new(pubsubMock.Producer)
+				productMock.On("Produce", mock.Anything, mock.Anything).
+					Return(errors.New("something unexpected happened"))
+
+				return productMock
+			},
+			wantErr: true,
+		},
+		{
+			name:   "success",
+			client: &mockClient{file: "rss.xml"},
+			fnMockProducer: func() pubsub.Producer {
+				productMock := new(pubsubMock.Producer)
+				productMock.On("Produce", mock.Anything, mock.MatchedBy(func(m *pubsub.Message) bool {
+					var batch newsBatch
+					err := json.Unmarshal(m.RawData, &batch)
+					return err == nil && batch.Provider == "arsenal" && len(batch.Articles) == 2 &&
+						batch.Articles[0].Title == "Arteta previews the north London derby"
+				})).Return(nil)
+
+				return productMock
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := config.Provider{Name: "arsenal", Parser: "rss", ListURL: "https://www.arsenal.com/rss"}
+			s := NewSyncer(provider, tt.client, tt.fnMockProducer(), &mockLog{})
+			if err := s.Sync(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("Sync() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
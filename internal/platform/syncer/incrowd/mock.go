@@ -1,10 +1,13 @@
-package brentfordFC
+package incrowd
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/patriciabonaldy/sports-news/internal/platform/genericClient"
@@ -29,37 +32,12 @@ func (m mockClient) Get(_ context.Context, _ string) (resp *http.Response, err e
 		return nil, errors.New("unknown error")
 	}
 
-	reader := io.NopCloser(strings.NewReader(`<NewListInformation>
-<ClubName>Brentford</ClubName>
-<ClubWebsiteURL>https://www.brentfordfc.com</ClubWebsiteURL>
-<NewsletterNewsItems>
-	<NewsletterNewsItem>
-		<ArticleURL>https://www.brentfordfc.com/news/2022/june/international-round-up-15.06.22/</ArticleURL>
-		<NewsID>641772</NewsID>
-		<PublishDate>2022-06-15 10:00:00</PublishDate>
-		<Taxonomies>Players</Taxonomies>
-		<TeaserText></TeaserText>
-		<ThumbnailImageURL>https://www.brentfordfc.com/api/image/feedassets/1fa93314-18a6-4180-bd1e-6e7a3549c969/Medium/mads-bidstrup-denmark-u21.jpg</ThumbnailImageURL>
-		<Title>Three wins for international Bees yesterday</Title>
-		<OptaMatchId></OptaMatchId>
-		<LastUpdateDate>2022-06-15 09:53:56</LastUpdateDate>
-		<IsPublished>True</IsPublished>
-	</NewsletterNewsItem>
-	<NewsletterNewsItem>
-		<ArticleURL>https://www.brentfordfc.com/news/2022/june/202122---brentfords-fourth-highest-league-finish/</ArticleURL>
-		<NewsID>641745</NewsID>
-		<PublishDate>2022-06-15 08:00:00</PublishDate>
-		<Taxonomies>History</Taxonomies>
-		<TeaserText></TeaserText>
-		<ThumbnailImageURL>https://www.brentfordfc.com/api/image/feedassets/377baa5d-ea74-41dc-9527-e73a8692c07c/Medium/dai-hopkins-brentford-v-portsmouth-1939.jpg</ThumbnailImageURL>
-		<Title>2021/22 - Brentford&apos;s fourth-highest league finish</Title>
-		<OptaMatchId></OptaMatchId>
-		<LastUpdateDate>2022-06-15 08:00:21</LastUpdateDate>
-		<IsPublished>True</IsPublished>
-		</NewsletterNewsItem>
-	</NewsletterNewsItems>
-</NewListInformation>
-		`))
+	data, err := os.ReadFile(filepath.Join("testdata", "brentford_list.xml"))
+	if err != nil {
+		return nil, err
+	}
+
+	reader := io.NopCloser(bytes.NewReader(data))
 	if m.wantErrorUnmarshall {
 		reader = io.NopCloser(strings.NewReader(`<NewListInformation`))
 	}
@@ -100,6 +78,9 @@ type mockLog struct{}
 func (m mockLog) Error(args ...interface{}) {
 }
 
+func (m mockLog) ErrorTrace(err error) {
+}
+
 func (m mockLog) Errorf(format string, args ...interface{}) {
 
 }
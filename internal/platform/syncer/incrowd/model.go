@@ -0,0 +1,78 @@
+package incrowd
+
+import "encoding/xml"
+
+// NewListInformation is the response of getnewlistinformation.
+type NewListInformation struct {
+	XMLName             xml.Name `xml:"NewListInformation"`
+	Text                string   `xml:",chardata"`
+	ClubName            string   `xml:"ClubName"`
+	ClubWebsiteURL      string   `xml:"ClubWebsiteURL"`
+	NewsletterNewsItems struct {
+		Text               string               `xml:",chardata"`
+		NewsletterNewsItem []NewsletterNewsItem `xml:"NewsletterNewsItem"`
+	} `xml:"NewsletterNewsItems"`
+}
+
+type NewsletterNewsItem struct {
+	Text              string `xml:",chardata"`
+	ArticleURL        string `xml:"ArticleURL"`
+	NewsArticleID     string `xml:"NewsArticleID"`
+	PublishDate       string `xml:"PublishDate"`
+	Taxonomies        string `xml:"Taxonomies"`
+	TeaserText        string `xml:"TeaserText"`
+	ThumbnailImageURL string `xml:"ThumbnailImageURL"`
+	Title             string `xml:"Title"`
+	OptaMatchId       string `xml:"OptaMatchId"`
+	LastUpdateDate    string `xml:"LastUpdateDate"`
+	IsPublished       string `xml:"IsPublished"`
+}
+
+// NewsArticleInformation is the response of getnewsarticleinformation.
+type NewsArticleInformation struct {
+	XMLName        xml.Name    `xml:"NewsArticleInformation"`
+	Text           string      `xml:",chardata"`
+	ClubName       string      `xml:"ClubName"`
+	ClubWebsiteURL string      `xml:"ClubWebsiteURL"`
+	NewsArticle    NewsArticle `xml:"NewsArticle"`
+}
+
+type NewsArticle struct {
+	Text              string   `xml:",chardata"`
+	ArticleURL        string   `xml:"ArticleURL"`
+	NewsArticleID     string   `xml:"NewsArticleID"`
+	PublishDate       string   `xml:"PublishDate"`
+	Taxonomies        string   `xml:"Taxonomies"`
+	TeaserText        string   `xml:"TeaserText"`
+	Subtitle          string   `xml:"Subtitle"`
+	ThumbnailImageURL string   `xml:"ThumbnailImageURL"`
+	Title             string   `xml:"Title"`
+	BodyText          RichText `xml:"BodyText"`
+	GalleryImageURLs  string   `xml:"GalleryImageURLs"`
+	VideoURL          string   `xml:"VideoURL"`
+	OptaMatchId       string   `xml:"OptaMatchId"`
+	LastUpdateDate    string   `xml:"LastUpdateDate"`
+	IsPublished       string   `xml:"IsPublished"`
+}
+
+// RichText keeps the markup of an element as it was published, to be
+// parsed with richtext.Parse.
+type RichText struct {
+	HTML string `xml:",innerxml"`
+}
+
+// ParseList decodes a getnewlistinformation response.
+func ParseList(data []byte) (NewListInformation, error) {
+	var list NewListInformation
+	err := xml.Unmarshal(data, &list)
+
+	return list, err
+}
+
+// ParseArticle decodes a getnewsarticleinformation response.
+func ParseArticle(data []byte) (NewsArticleInformation, error) {
+	var article NewsArticleInformation
+	err := xml.Unmarshal(data, &article)
+
+	return article, err
+}
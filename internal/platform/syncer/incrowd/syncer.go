@@ -0,0 +1,142 @@
+package incrowd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
+
+	"github.com/patriciabonaldy/sports-news/internal/platform/genericClient"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/pubsub"
+	"github.com/patriciabonaldy/sports-news/internal/platform/syncer"
+)
+
+const (
+	listPath     = "/api/incrowd/getnewlistinformation?count=%d"
+	articlePath  = "/api/incrowd/getnewsarticleinformation?id=%s"
+	defaultCount = 50
+)
+
+// Club is a club whose news are hosted on the incrowd platform.
+type Club struct {
+	ID      string
+	BaseURL string
+}
+
+// ListURL returns the URL of the latest news of the club.
+func (c Club) ListURL() string {
+	return strings.TrimSuffix(c.BaseURL, "/") + fmt.Sprintf(listPath, defaultCount)
+}
+
+// ArticleURL returns the template of the URL of the detail of an article.
+func (c Club) ArticleURL() string {
+	return strings.TrimSuffix(c.BaseURL, "/") + articlePath
+}
+
+type SyncerNews struct {
+	client     genericClient.Client
+	producer   pubsub.Producer
+	log        logger.Logger
+	provider   string
+	url        string
+	articleURL string
+}
+
+// newsBatch is the message published for the pipeline, it carries
+// the provider and where to fetch the detail of every article.
+type newsBatch struct {
+	Provider   string               `json:"provider"`
+	ArticleURL string               `json:"article_url"`
+	Items      []NewsletterNewsItem `json:"items"`
+}
+
+var _ syncer.Syncer = &SyncerNews{}
+
+var _ syncer.Factory = NewSyncer
+
+// NewSyncer returns the syncer of an incrowd provider. The name of the
+// provider is the ID of the club, list_url and article_url default to
+// the incrowd endpoints of base_url.
+func NewSyncer(provider config.Provider, client genericClient.Client, producer pubsub.Producer, log logger.Logger) syncer.Syncer {
+	s := NewClubSyncer(Club{ID: provider.Name, BaseURL: provider.BaseURL}, client, producer, log)
+	if provider.ListURL != "" {
+		s.url = provider.ListURL
+	}
+
+	if provider.ArticleURL != "" {
+		s.articleURL = provider.ArticleURL
+	}
+
+	return s
+}
+
+// NewClubSyncer returns the syncer of an incrowd-hosted club.
+func NewClubSyncer(club Club, client genericClient.Client, producer pubsub.Producer, log logger.Logger) *SyncerNews {
+	return &SyncerNews{
+		client:     client,
+		producer:   producer,
+		log:        log,
+		provider:   club.ID,
+		url:        club.ListURL(),
+		articleURL: club.ArticleURL(),
+	}
+}
+
+func (s *SyncerNews) Sync(ctx context.Context) error {
+	resp, err := s.client.Get(ctx, s.url)
+	if err != nil {
+		s.log.Errorf("error fetch - sync %s", err)
+		return err
+	}
+
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		s.log.Errorf("error read body - sync %s", err)
+		return err
+	}
+
+	newListInf, err := ParseList(data)
+	if err != nil {
+		s.log.Errorf("error unmarshall - sync %s", err)
+		return err
+	}
+
+	m, err := generateMessage(newsBatch{
+		Provider:   s.provider,
+		ArticleURL: s.articleURL,
+		Items:      newListInf.NewsletterNewsItems.NewsletterNewsItem,
+	})
+	if err != nil {
+		s.log.Errorf("error generate message - sync %s", err)
+		return err
+	}
+
+	err = s.producer.Produce(ctx, m)
+	if err != nil {
+		s.log.Errorf("error producer - sync %s", err)
+		return err
+	}
+
+	return nil
+}
+
+func generateMessage(batch newsBatch) (*pubsub.Message, error) {
+	message, err := pubsub.NewSystemMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	message.RawData = data
+
+	return &message, nil
+}
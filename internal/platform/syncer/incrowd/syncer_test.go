@@ -0,0 +1,157 @@
+package incrowd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/sports-news/internal/platform/genericClient"
+	"github.com/patriciabonaldy/sports-news/internal/platform/pubsub"
+	"github.com/patriciabonaldy/sports-news/internal/platform/pubsub/pubsubMock"
+	"github.com/patriciabonaldy/sports-news/internal/platform/richtext"
+)
+
+func TestSyncerNews_Sync(t *testing.T) {
+	type fields struct {
+		client         genericClient.Client
+		fnMockProducer func() pubsub.Producer
+		url            string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr bool
+	}{
+		{
+			name: "Error in get",
+			fields: fields{
+				client:         &mockClient{wantError: true},
+				fnMockProducer: func() pubsub.Producer { return nil },
+				url:            "",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error unmarshall body response",
+			fields: fields{
+				client:         &mockClient{wantErrorUnmarshall: true},
+				fnMockProducer: func() pubsub.Producer { return nil },
+				url:            "",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error publish message",
+			fields: fields{
+				client: &mockClient{},
+				fnMockProducer: func() pubsub.Producer {
+					productMock := new(pubsubMock.Producer)
+					productMock.On("Produce", mock.Anything, mock.Anything).
+						Return(errors.New("something unexpected happened"))
+
+					return productMock
+				},
+				url: "",
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			fields: fields{
+				client: &mockClient{},
+				fnMockProducer: func() pubsub.Producer {
+					productMock := new(pubsubMock.Producer)
+					productMock.On("Produce", mock.Anything, mock.MatchedBy(func(m *pubsub.Message) bool {
+						var batch newsBatch
+						err := json.Unmarshal(m.RawData, &batch)
+						return err == nil && batch.Provider == "brentford" &&
+							batch.ArticleURL == "https://www.brentfordfc.com/api/incrowd/getnewsarticleinformation?id=%s" &&
+							len(batch.Items) == 2
+					})).Return(nil)
+
+					return productMock
+				},
+				url: "",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SyncerNews{
+				log:        &mockLog{},
+				client:     tt.fields.client,
+				producer:   tt.fields.fnMockProducer(),
+				provider:   "brentford",
+				url:        tt.fields.url,
+				articleURL: "https://www.brentfordfc.com/api/incrowd/getnewsarticleinformation?id=%s",
+			}
+			if err := s.Sync(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("Sync() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestSyncerNews_Sync_golden(t *testing.T) {
+	var got *pubsub.Message
+	producerMock := new(pubsubMock.Producer)
+	producerMock.On("Produce", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { got = args.Get(1).(*pubsub.Message) }).
+		Return(nil)
+
+	club := Club{ID: "brentford", BaseURL: "https://www.brentfordfc.com/"}
+	s := NewClubSyncer(club, &mockClient{}, producerMock, &mockLog{})
+	require.NoError(t, s.Sync(context.Background()))
+	require.NotNil(t, got)
+
+	var batch bytes.Buffer
+	require.NoError(t, json.Indent(&batch, got.RawData, "", "  "))
+	assertGolden(t, "brentford_list.golden.json", batch.Bytes())
+}
+
+func TestParseArticle_golden(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "brentford_article.xml"))
+	require.NoError(t, err)
+
+	article, err := ParseArticle(data)
+	require.NoError(t, err)
+
+	got, err := json.MarshalIndent(article, "", "  ")
+	require.NoError(t, err)
+	assertGolden(t, "brentford_article.golden.json", got)
+
+	body, err := json.MarshalIndent(richtext.Parse(article.NewsArticle.BodyText.HTML), "", "  ")
+	require.NoError(t, err)
+	assertGolden(t, "brentford_body.golden.json", body)
+}
+
+func TestClub(t *testing.T) {
+	club := Club{ID: "brentford", BaseURL: "https://www.brentfordfc.com/"}
+	assert.Equal(t, "https://www.brentfordfc.com/api/incrowd/getnewlistinformation?count=50", club.ListURL())
+	assert.Equal(t, "https://www.brentfordfc.com/api/incrowd/getnewsarticleinformation?id=%s", club.ArticleURL())
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	golden := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(golden, got, 0o644))
+	}
+
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
@@ -0,0 +1,38 @@
+package syncer
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
+	"github.com/patriciabonaldy/sports-news/internal/platform/genericClient"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/pubsub"
+)
+
+// Factory creates the Syncer of a provider.
+type Factory func(provider config.Provider, client genericClient.Client, producer pubsub.Producer, log logger.Logger) Syncer
+
+// Registry maps the parser of a provider to the Factory of its Syncer.
+type Registry struct {
+	factories map[string]Factory
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// Register adds the factory of a parser, replacing any previous one.
+func (r *Registry) Register(parser string, factory Factory) {
+	r.factories[parser] = factory
+}
+
+// New returns the Syncer of the provider.
+func (r *Registry) New(provider config.Provider, client genericClient.Client, producer pubsub.Producer, log logger.Logger) (Syncer, error) {
+	factory, ok := r.factories[provider.Parser]
+	if !ok {
+		return nil, errors.Errorf("provider %s: unknown parser %q", provider.Name, provider.Parser)
+	}
+
+	return factory(provider, client, producer, log), nil
+}
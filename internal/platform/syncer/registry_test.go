@@ -0,0 +1,38 @@
+package syncer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
+	"github.com/patriciabonaldy/sports-news/internal/platform/genericClient"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/pubsub"
+)
+
+type syncerFunc func(context.Context) error
+
+func (f syncerFunc) Sync(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestRegistry_New(t *testing.T) {
+	var got config.Provider
+	registry := NewRegistry()
+	registry.Register("incrowd", func(provider config.Provider, _ genericClient.Client, _ pubsub.Producer, _ logger.Logger) Syncer {
+		got = provider
+		return syncerFunc(func(context.Context) error { return nil })
+	})
+
+	provider := config.Provider{Name: "brentford", Parser: "incrowd"}
+	s, err := registry.New(provider, nil, nil, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, s)
+	assert.Equal(t, provider, got)
+
+	_, err = registry.New(config.Provider{Name: "arsenal", Parser: "rss"}, nil, nil, nil)
+	assert.Error(t, err)
+}
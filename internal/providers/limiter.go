@@ -0,0 +1,52 @@
+package providers
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// hostLimiter limits the requests per second sent to every host, so
+// that the providers of a platform share the limit of its API.
+type hostLimiter struct {
+	mu       sync.Mutex
+	limit    rate.Limit
+	limiters map[string]*rate.Limiter
+}
+
+// newHostLimiter returns a limiter of perSecond requests to each host,
+// it does not limit them when perSecond is zero.
+func newHostLimiter(perSecond float64) *hostLimiter {
+	limit := rate.Inf
+	if perSecond > 0 {
+		limit = rate.Limit(perSecond)
+	}
+
+	return &hostLimiter{limit: limit, limiters: make(map[string]*rate.Limiter)}
+}
+
+// Wait blocks until a request to the host of rawURL is allowed or the
+// context is done.
+func (l *hostLimiter) Wait(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	return l.limiter(u.Host).Wait(ctx)
+}
+
+func (l *hostLimiter) limiter(host string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.limiters[host]
+	if !ok {
+		limiter = rate.NewLimiter(l.limit, 1)
+		l.limiters[host] = limiter
+	}
+
+	return limiter
+}
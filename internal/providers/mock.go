@@ -6,13 +6,17 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/patriciabonaldy/sports-news/internal/platform/genericClient"
+	"github.com/patriciabonaldy/sports-news/internal/platform/syncer/incrowd"
 )
 
 type mockClient struct {
 	wantError           bool
 	wantErrorUnmarshall bool
+	body                string
 }
 
 func (m mockClient) Delete(_ context.Context, _ string, _ ...genericClient.Header) error {
@@ -51,6 +55,10 @@ func (m mockClient) Get(_ context.Context, _ string) (resp *http.Response, err e
 		reader = io.NopCloser(strings.NewReader(`<NewListInformation`))
 	}
 
+	if m.body != "" {
+		reader = io.NopCloser(strings.NewReader(m.body))
+	}
+
 	return &http.Response{
 		StatusCode:    http.StatusCreated,
 		Proto:         "HTTP/1.1",
@@ -82,11 +90,116 @@ func (m mockClient) Post(_ context.Context, _ string, _ []byte, _ ...genericClie
 
 var _ genericClient.Client = &mockClient{}
 
-func mockNewsletterNewsItem() NewsletterNewsItem {
-	return NewsletterNewsItem{
+// concurrentClient is a mockClient that counts the calls to Get and the
+// most that ran at the same time.
+type concurrentClient struct {
+	wantError bool
+	body      string
+
+	running int64
+	calls   int64
+	max     int64
+}
+
+func (c *concurrentClient) Delete(ctx context.Context, url string, headers ...genericClient.Header) error {
+	return mockClient{wantError: c.wantError}.Delete(ctx, url, headers...)
+}
+
+func (c *concurrentClient) Get(ctx context.Context, url string) (*http.Response, error) {
+	atomic.AddInt64(&c.calls, 1)
+	running := atomic.AddInt64(&c.running, 1)
+	defer atomic.AddInt64(&c.running, -1)
+	for {
+		max := atomic.LoadInt64(&c.max)
+		if running <= max || atomic.CompareAndSwapInt64(&c.max, max, running) {
+			break
+		}
+	}
+
+	// give the other workers the time to start
+	time.Sleep(time.Millisecond)
+
+	return mockClient{wantError: c.wantError, body: c.body}.Get(ctx, url)
+}
+
+func (c *concurrentClient) Post(ctx context.Context, url string, body []byte, headers ...genericClient.Header) (*http.Response, error) {
+	return mockClient{wantError: c.wantError}.Post(ctx, url, body, headers...)
+}
+
+func mockNewsletterNewsItem() incrowd.NewsletterNewsItem {
+	return incrowd.NewsletterNewsItem{
 		ArticleURL:    "https://www.brentfordfc.com/news/2022/june/pontus-explains-how-fatherhood-has-calmed-him-down",
 		NewsArticleID: "641838",
 		Title:         "Pontus explains",
 		IsPublished:   "true",
 	}
 }
+
+func mockNewsBatch() NewsBatch {
+	return NewsBatch{
+		Provider:   "brentford",
+		ArticleURL: "https://www.brentfordfc.com/api/incrowd/getnewsarticleinformation?id=%s",
+		Items:      []incrowd.NewsletterNewsItem{mockNewsletterNewsItem()},
+	}
+}
+
+func mockBatchMessage() batchMessage {
+	batch := mockNewsBatch()
+	return batchMessage{Provider: batch.Provider, ArticleURL: batch.ArticleURL, Items: batch.Items}
+}
+
+func mockNewsArticleInformation(title, lastUpdateDate string) string {
+	return `<NewsArticleInformation>
+<ClubName>Brentford</ClubName>
+<ClubWebsiteURL>https://www.brentfordfc.com</ClubWebsiteURL>
+<NewsArticle>
+	<ArticleURL>https://www.brentfordfc.com/news/2022/june/pontus-explains-how-fatherhood-has-calmed-him-down</ArticleURL>
+	<NewsArticleID>641838</NewsArticleID>
+	<PublishDate>2022-06-15 08:00:00</PublishDate>
+	<Taxonomies>Players, Interviews</Taxonomies>
+	<Title>` + title + `</Title>
+	<BodyText><p>Pontus Jansson explains</p></BodyText>
+	<GalleryImageURLs>https://www.brentfordfc.com/a.jpg,https://www.brentfordfc.com/b.jpg</GalleryImageURLs>
+	<LastUpdateDate>` + lastUpdateDate + `</LastUpdateDate>
+	<IsPublished>True</IsPublished>
+</NewsArticle>
+</NewsArticleInformation>`
+}
+
+// blockingClient is a mockClient whose Get blocks until release is
+// closed or the context is done, started receives a value for every call.
+type blockingClient struct {
+	mockClient
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *blockingClient) Get(ctx context.Context, url string) (*http.Response, error) {
+	c.started <- struct{}{}
+	select {
+	case <-c.release:
+		return c.mockClient.Get(ctx, url)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// flakyClient is a mockClient whose first failures calls to Get fail,
+// with err when it is set.
+type flakyClient struct {
+	mockClient
+	failures int64
+	err      error
+	calls    int64
+}
+
+func (c *flakyClient) Get(ctx context.Context, url string) (*http.Response, error) {
+	if atomic.AddInt64(&c.calls, 1) <= c.failures {
+		if c.err != nil {
+			return nil, c.err
+		}
+		return nil, errors.New("unknown error")
+	}
+
+	return c.mockClient.Get(ctx, url)
+}
@@ -1,52 +1,36 @@
 package providers
 
-import "encoding/xml"
+import (
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/syncer"
+	"github.com/patriciabonaldy/sports-news/internal/platform/syncer/incrowd"
+)
 
-type NewsletterNewsItem struct {
-	Text              string `xml:",chardata"`
-	ArticleURL        string `xml:"ArticleURL"`
-	NewsArticleID     string `xml:"NewsArticleID"`
-	PublishDate       string `xml:"PublishDate"`
-	Taxonomies        string `xml:"Taxonomies"`
-	TeaserText        string `xml:"TeaserText"`
-	ThumbnailImageURL string `xml:"ThumbnailImageURL"`
-	Title             string `xml:"Title"`
-	OptaMatchId       string `xml:"OptaMatchId"`
-	LastUpdateDate    string `xml:"LastUpdateDate"`
-	IsPublished       string `xml:"IsPublished"`
+// NewsBatch is the list of articles published by a provider syncer.
+// Items are fetched from ArticleURL, the template of the URL of the
+// detail of every article, while Articles come complete from feeds
+// like RSS and Atom and are stored as they are.
+type NewsBatch struct {
+	Provider   string
+	ArticleURL string
+	Items      []incrowd.NewsletterNewsItem
+	Articles   []internal.ArticleNews
 }
 
-type NewsArticle struct {
-	Text              string `xml:",chardata"`
-	ArticleURL        string `xml:"ArticleURL"`
-	NewsArticleID     string `xml:"NewsArticleID"`
-	PublishDate       string `xml:"PublishDate"`
-	Taxonomies        string `xml:"Taxonomies"`
-	TeaserText        string `xml:"TeaserText"`
-	Subtitle          string `xml:"Subtitle"`
-	ThumbnailImageURL string `xml:"ThumbnailImageURL"`
-	Title             string `xml:"Title"`
-	BodyText          struct {
-		Text string `xml:",chardata"`
-		P    []struct {
-			Text string `xml:",chardata"`
-			A    struct {
-				Text string `xml:",chardata"`
-				Href string `xml:"href,attr"`
-			} `xml:"a"`
-		} `xml:"p"`
-	} `xml:"BodyText"`
-	GalleryImageURLs string `xml:"GalleryImageURLs"`
-	VideoURL         string `xml:"VideoURL"`
-	OptaMatchId      string `xml:"OptaMatchId"`
-	LastUpdateDate   string `xml:"LastUpdateDate"`
-	IsPublished      string `xml:"IsPublished"`
+// batchMessage is the wire format of a NewsBatch, as the syncers publish
+// it.
+type batchMessage struct {
+	Provider   string                       `json:"provider"`
+	ArticleURL string                       `json:"article_url,omitempty"`
+	Items      []incrowd.NewsletterNewsItem `json:"items,omitempty"`
+	Articles   []syncer.Article             `json:"articles,omitempty"`
 }
 
-type NewsArticleInformation struct {
-	XMLName        xml.Name    `xml:"NewsArticleInformation"`
-	Text           string      `xml:",chardata"`
-	ClubName       string      `xml:"ClubName"`
-	ClubWebsiteURL string      `xml:"ClubWebsiteURL"`
-	NewsArticle    NewsArticle `xml:"NewsArticle"`
+func (m batchMessage) toBatch() NewsBatch {
+	batch := NewsBatch{Provider: m.Provider, ArticleURL: m.ArticleURL, Items: m.Items}
+	for _, article := range m.Articles {
+		batch.Articles = append(batch.Articles, article.ToArticle())
+	}
+
+	return batch
 }
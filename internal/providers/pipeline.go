@@ -2,128 +2,461 @@ package providers
 
 import (
 	"context"
-	"encoding/json"
-	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
 	"github.com/patriciabonaldy/sports-news/internal"
 	"github.com/patriciabonaldy/sports-news/internal/platform/genericClient"
 	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/richtext"
+	"github.com/patriciabonaldy/sports-news/internal/platform/syncer/incrowd"
 )
 
 type Pipeline interface {
-	Process(ctx context.Context, data []NewsletterNewsItem)
+	// Process stores the articles of the batch. It always returns once
+	// every item finished, failed or was canceled with the context.
+	Process(ctx context.Context, batch NewsBatch) RunReport
+	// Progress returns the counters of the items of the batches processed
+	// since the pipeline was created.
+	Progress() Progress
+	// Retry processes the item of a dead letter again, it returns the
+	// error of the item when it fails again.
+	Retry(ctx context.Context, deadLetter internal.DeadLetter) error
+}
+
+// maxBackoff bounds the wait between two attempts of a fetch.
+const maxBackoff = 30 * time.Second
+
+// Progress counts the items whose detail is fetched: Queued wait for a
+// worker, InFlight are being fetched, parsed and stored, Done and Failed
+// are finished, the canceled ones count as failed.
+type Progress struct {
+	Queued   int64
+	InFlight int64
+	Done     int64
+	Failed   int64
 }
 
 type pipeLine struct {
 	repository internal.Storage
 	client     genericClient.Client
-	log        logger.Logger
+	limiter    *hostLimiter
+	workers    int
+	queueSize  int
+	attempts   int
+	backoff    time.Duration
+	// progress is updated with atomic operations.
+	progress Progress
+	log      logger.Logger
 }
 
-func NewPipeLine(repository internal.Storage, client genericClient.Client, log logger.Logger) Pipeline {
-	return &pipeLine{repository: repository, client: client, log: log}
+// item is an item of the list of a provider on its way through the
+// stages. Failed items carry their error to the end of the pipeline.
+type item struct {
+	id       string
+	started  time.Time
+	stage    Stage
+	attempts int
+	body     []byte
+	article  incrowd.NewsArticleInformation
+	err      error
 }
 
-func (p *pipeLine) Process(ctx context.Context, data []NewsletterNewsItem) {
-	ch1 := p.taskFetch(ctx, data)
+// NewPipeLine returns a pipeline that fetches the detail of the items
+// with the limits and retries of cfg. A RatePerHost that is not positive
+// does not limit the requests, and an Attempts below 2 does not retry
+// them.
+func NewPipeLine(repository internal.Storage, client genericClient.Client, cfg config.Pipeline, log logger.Logger) Pipeline {
+	workers := cfg.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	attempts := cfg.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	return &pipeLine{
+		repository: repository,
+		client:     client,
+		limiter:    newHostLimiter(cfg.RatePerHost),
+		workers:    workers,
+		queueSize:  cfg.QueueSize,
+		attempts:   attempts,
+		backoff:    time.Duration(cfg.BackoffMillis) * time.Millisecond,
+		log:        log,
+	}
+}
+
+func (p *pipeLine) Process(ctx context.Context, batch NewsBatch) RunReport {
+	report := RunReport{Provider: batch.Provider, Started: time.Now().UTC()}
+	ch1 := p.taskFetch(ctx, batch.ArticleURL, batch.Items)
 	ch2 := p.taskParse(ch1)
 
+	withdrawal, withdraw := withdrawalOf(batch, time.Now().UTC())
+
+	for _, article := range batch.Articles {
+		article.Provider = batch.Provider
+		itemReport := p.store(ctx, article, time.Now())
+		p.settle(ctx, batch.Provider, "", itemReport, article.IsPublished)
+		report.add(itemReport)
+	}
+
+	for it := range ch2 {
+		var itemReport ItemReport
+		if it.err != nil {
+			itemReport = p.failed(ctx, it)
+		} else {
+			article := toArticle(batch.Provider, it.article)
+			article.Provider = batch.Provider
+			// the list is updated before the detail when a club pulls a story.
+			if withdrawal.IsUnpublished(article.NewsID) {
+				article.IsPublished = false
+			}
+
+			itemReport = p.store(ctx, article, it.started)
+			itemReport.ID = it.id
+			itemReport.Attempts = it.attempts
+		}
+
+		p.settle(ctx, batch.Provider, batch.ArticleURL, itemReport, !withdrawal.IsUnpublished(articleID(batch.Provider, it.id)))
+		p.finish(itemReport.Outcome)
+		report.add(itemReport)
+	}
+
+	report.Err = ctx.Err()
+	if withdraw && report.Err == nil {
+		result, err := p.repository.Withdraw(ctx, withdrawal)
+		if err != nil {
+			p.log.Errorf("error Withdraw articles of %s %s", batch.Provider, err.Error())
+		}
+
+		report.Stats.Deleted = result.Deleted
+		report.Stats.Unpublished = result.Unpublished
+	}
+
+	report.Duration = time.Since(report.Started)
+
+	return report
+}
+
+func (p *pipeLine) Progress() Progress {
+	return Progress{
+		Queued:   atomic.LoadInt64(&p.progress.Queued),
+		InFlight: atomic.LoadInt64(&p.progress.InFlight),
+		Done:     atomic.LoadInt64(&p.progress.Done),
+		Failed:   atomic.LoadInt64(&p.progress.Failed),
+	}
+}
+
+// finish counts an item that is no longer in flight.
+func (p *pipeLine) finish(outcome Outcome) {
+	atomic.AddInt64(&p.progress.InFlight, -1)
+	if outcome == OutcomeFailed || outcome == OutcomeCanceled {
+		atomic.AddInt64(&p.progress.Failed, 1)
+		return
+	}
+
+	atomic.AddInt64(&p.progress.Done, 1)
+}
+
+// failed returns the report of an item that failed before it was stored,
+// items that failed because the context is done are canceled.
+func (p *pipeLine) failed(ctx context.Context, it item) ItemReport {
+	outcome := OutcomeFailed
+	if ctx.Err() != nil {
+		outcome = OutcomeCanceled
+	}
+
+	return ItemReport{
+		ID:       it.id,
+		Stage:    it.stage,
+		Outcome:  outcome,
+		Err:      it.err,
+		Attempts: it.attempts,
+		Duration: time.Since(it.started),
+	}
+}
+
+// errNotRefetchable is the error of the retry of a dead letter of a
+// complete article, which has no detail to fetch again.
+var errNotRefetchable = errors.New("the article has no detail to fetch, the next sync of its provider stores it again")
+
+// Retry processes the item of the dead letter as a batch of its own,
+// which withdraws nothing. The dead letter is deleted once the article
+// is stored, and replaced when it fails again. The dead letters of the
+// complete articles of a feed are kept, until the next sync stores them.
+func (p *pipeLine) Retry(ctx context.Context, deadLetter internal.DeadLetter) error {
+	if deadLetter.ArticleURL == "" {
+		return errNotRefetchable
+	}
+
+	report := p.Process(ctx, NewsBatch{
+		Provider:   deadLetter.Provider,
+		ArticleURL: deadLetter.ArticleURL,
+		Items: []incrowd.NewsletterNewsItem{{
+			NewsArticleID: deadLetter.ArticleID,
+			IsPublished:   strconv.FormatBool(deadLetter.IsPublished),
+		}},
+	})
+
+	return report.Items[0].Err
+}
+
+// settle keeps the dead letter of an item that failed and deletes the
+// one of an item that was stored. The complete articles of a feed have
+// no articleURL to fetch them again.
+func (p *pipeLine) settle(ctx context.Context, provider, articleURL string, report ItemReport, published bool) {
+	switch report.Outcome {
+	case OutcomeFailed:
+		p.saveDeadLetter(ctx, provider, articleURL, report, published)
+	case OutcomeCanceled:
+	default:
+		p.deleteDeadLetter(ctx, provider, report.ID)
+	}
+}
+
+func (p *pipeLine) saveDeadLetter(ctx context.Context, provider, articleURL string, report ItemReport, published bool) {
+	err := p.repository.SaveDeadLetter(ctx, internal.DeadLetter{
+		ArticleID:   report.ID,
+		Provider:    provider,
+		ArticleURL:  articleURL,
+		IsPublished: published,
+		Stage:       string(report.Stage),
+		Error:       report.Err.Error(),
+		Attempts:    report.Attempts,
+		FailedAt:    time.Now().UTC(),
+	})
+	if err != nil {
+		p.log.Errorf("error SaveDeadLetter articleID %s %s", report.ID, err.Error())
+	}
+}
+
+// deleteDeadLetter deletes the dead letter of an item that was stored,
+// most items have none.
+func (p *pipeLine) deleteDeadLetter(ctx context.Context, provider, articleID string) {
+	err := p.repository.DeleteDeadLetter(ctx, provider, articleID)
+	if err != nil && !errors.Is(err, internal.ErrDeadLetterNotFound) {
+		p.log.Errorf("error DeleteDeadLetter %s articleID %s %s", provider, articleID, err.Error())
+	}
+}
+
+// withdrawalOf returns the list of the batch to find pulled articles.
+// Batches without provider, articles or publish dates are not lists
+// that can be trusted to be complete, so they withdraw nothing.
+func withdrawalOf(batch NewsBatch, at time.Time) (internal.Withdrawal, bool) {
+	withdrawal := internal.Withdrawal{Provider: batch.Provider, At: at}
+	add := func(id string, published bool, publishDate time.Time) {
+		withdrawal.Listed = append(withdrawal.Listed, id)
+		if !published {
+			withdrawal.Unpublished = append(withdrawal.Unpublished, id)
+		}
+
+		if !publishDate.IsZero() && (withdrawal.Since.IsZero() || publishDate.Before(withdrawal.Since)) {
+			withdrawal.Since = publishDate
+		}
+	}
+
+	for _, item := range batch.Items {
+		published, _ := strconv.ParseBool(item.IsPublished)
+		publishDate, _ := internal.ParsePublishDate(item.PublishDate)
+		add(articleID(batch.Provider, item.NewsArticleID), published, publishDate)
+	}
+
+	for _, article := range batch.Articles {
+		add(article.NewsID, article.IsPublished, article.PublishDate)
+	}
+
+	return withdrawal, batch.Provider != "" && len(withdrawal.Listed) > 0 && !withdrawal.Since.IsZero()
+}
+
+// store upserts the article unless the context is done, started is when
+// the processing of the article began.
+func (p *pipeLine) store(ctx context.Context, article internal.ArticleNews, started time.Time) ItemReport {
+	report := ItemReport{ID: article.NewsID, Stage: StageStore}
+	if err := ctx.Err(); err != nil {
+		report.Outcome = OutcomeCanceled
+		report.Err = err
+		report.Duration = time.Since(started)
+		return report
+	}
+
+	result, err := p.repository.Upsert(ctx, article)
+	report.Duration = time.Since(started)
+	if err != nil {
+		report.Outcome = OutcomeFailed
+		report.Err = err
+		return report
+	}
+
+	report.Outcome = outcomeOf(result)
+
+	return report
+}
+
+// taskFetch fetches the detail of the items with the workers of the
+// pipeline, at most queueSize items wait for a worker or for the parser.
+// Items left when the context is done are not fetched, they fail with
+// its error. The channel is closed once every item was sent.
+func (p *pipeLine) taskFetch(ctx context.Context, articleURL string, data []incrowd.NewsletterNewsItem) chan item {
+	atomic.AddInt64(&p.progress.Queued, int64(len(data)))
+	queue := make(chan incrowd.NewsletterNewsItem, p.queueSize)
+	go func() {
+		defer close(queue)
+		for _, d := range data {
+			queue <- d
+		}
+	}()
+
+	ch1 := make(chan item, p.queueSize)
 	var wg sync.WaitGroup
-	wg.Add(len(data))
-	go func(ch2 chan NewsArticleInformation) {
-		for m := range ch2 {
-			article := toArticle(m)
-			_, err := p.repository.GetArticleByID(ctx, article.NewsID)
-			if err != nil {
-				err = p.repository.Save(ctx, article)
-				if err != nil {
-					p.log.Errorf("error Create ArticleNews %s", err.Error())
+	wg.Add(p.workers)
+	for i := 0; i < p.workers; i++ {
+		go func() {
+			defer wg.Done()
+			for d := range queue {
+				atomic.AddInt64(&p.progress.Queued, -1)
+				atomic.AddInt64(&p.progress.InFlight, 1)
+				it := item{id: d.NewsArticleID, started: time.Now(), stage: StageFetch}
+				if it.err = ctx.Err(); it.err == nil {
+					it.body, it.attempts, it.err = p.fetchWithRetries(ctx, fmt.Sprintf(articleURL, d.NewsArticleID))
 				}
+
+				ch1 <- it
 			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(ch1)
+	}()
 
-			wg.Done()
+	return ch1
+}
+
+// fetchWithRetries fetches the detail of an article, retrying a failed
+// fetch until the attempts of the pipeline run out. A detail that is not
+// found is not retried. It returns the number of attempts made.
+func (p *pipeLine) fetchWithRetries(ctx context.Context, url string) ([]byte, int, error) {
+	for attempt := 1; ; attempt++ {
+		body, err := p.fetch(ctx, url)
+		if err == nil || errors.Is(err, genericClient.ErrNotFound) || attempt >= p.attempts || ctx.Err() != nil {
+			return body, attempt, err
 		}
-	}(ch2)
 
-	wg.Wait()
+		select {
+		case <-time.After(p.backoffOf(attempt)):
+		case <-ctx.Done():
+			return nil, attempt, ctx.Err()
+		}
+	}
 }
 
-func (p *pipeLine) taskFetch(ctx context.Context, data []NewsletterNewsItem) chan []byte {
-	ch1 := make(chan []byte)
-	for _, d := range data {
-		go func(ch chan []byte, id string) {
-			resp, err := p.client.Get(ctx, fmt.Sprintf("https://www.brentfordfc.com/api/incrowd/getnewsarticleinformation?id=%s", id))
-			if err != nil {
-				p.log.Errorf("error fetch - sync articleID %s, error %s", id, err)
-				return
-			}
+// backoffOf returns the wait after the given attempt: the backoff of the
+// pipeline doubled for every previous attempt, up to maxBackoff, of which
+// a random half is jitter so that the retries of the workers spread.
+func (p *pipeLine) backoffOf(attempt int) time.Duration {
+	if p.backoff <= 0 {
+		return 0
+	}
 
-			defer resp.Body.Close()
+	wait := p.backoff
+	for i := 1; i < attempt && wait < maxBackoff; i++ {
+		wait *= 2
+	}
 
-			body, err := io.ReadAll(resp.Body)
-			if err != nil {
-				p.log.Errorf("error read body - sync %s", err)
-				return
-			}
+	if wait > maxBackoff {
+		wait = maxBackoff
+	}
 
-			ch <- body
-		}(ch1, d.NewsArticleID)
+	half := wait / 2
 
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// fetch returns the body of the detail of an article, once the host
+// allows another request.
+func (p *pipeLine) fetch(ctx context.Context, url string) ([]byte, error) {
+	if err := p.limiter.Wait(ctx, url); err != nil {
+		return nil, err
 	}
 
-	return ch1
+	resp, err := p.client.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
 }
 
-func (p *pipeLine) taskParse(ch1 chan []byte) chan NewsArticleInformation {
-	ch2 := make(chan NewsArticleInformation)
+// taskParse parses the fetched details, failed items are passed on as
+// they are. The channel is closed once ch1 is.
+func (p *pipeLine) taskParse(ch1 chan item) chan item {
+	ch2 := make(chan item, p.queueSize)
 
-	go func(ch1 chan []byte, ch2 chan NewsArticleInformation) {
-		for data := range ch1 {
-			var newArticle NewsArticleInformation
-			err := xml.Unmarshal(data, &newArticle)
-			if err != nil {
-				p.log.Errorf("error unmarshall - sync %s", err)
-				return
+	go func(ch1 chan item, ch2 chan item) {
+		defer close(ch2)
+		for it := range ch1 {
+			if it.err == nil {
+				it.stage = StageParse
+				it.article, it.err = incrowd.ParseArticle(it.body)
+				it.body = nil
 			}
 
-			ch2 <- newArticle
+			ch2 <- it
 		}
 	}(ch1, ch2)
 
 	return ch2
 }
 
-func toArticle(msg NewsArticleInformation) internal.ArticleNews {
+// articleID prefixes the ID of an incrowd article with the provider, the
+// IDs are only unique within a club.
+func articleID(provider, id string) string {
+	return provider + "-" + id
+}
+
+func toArticle(provider string, msg incrowd.NewsArticleInformation) internal.ArticleNews {
 	newsArticle := msg.NewsArticle
-	text := ""
-	if data, err := json.Marshal(newsArticle.BodyText); err == nil {
-		text = string(data)
-	}
+	body := richtext.Parse(newsArticle.BodyText.HTML)
 
 	isPublished, err := strconv.ParseBool(newsArticle.IsPublished)
 	if err != nil {
 		isPublished = false
 	}
 
+	publishDate, err := internal.ParsePublishDate(newsArticle.PublishDate)
+	if err != nil {
+		publishDate = time.Time{}
+	}
+
 	article := internal.ArticleNews{
-		NewsID:            newsArticle.NewsArticleID,
+		NewsID:            articleID(provider, newsArticle.NewsArticleID),
 		Title:             newsArticle.Title,
 		Subtitle:          newsArticle.Subtitle,
 		ClubName:          msg.ClubName,
 		ClubWebsiteURL:    msg.ClubWebsiteURL,
 		ArticleURL:        newsArticle.ArticleURL,
-		BodyText:          text,
-		GalleryImageURLs:  newsArticle.GalleryImageURLs,
+		BodyText:          body.Text(),
+		Body:              body,
+		GalleryImageURLs:  internal.SplitList(newsArticle.GalleryImageURLs),
 		VideoURL:          newsArticle.VideoURL,
-		Taxonomies:        newsArticle.Taxonomies,
+		Taxonomies:        internal.SplitList(newsArticle.Taxonomies),
 		TeaserText:        newsArticle.TeaserText,
 		ThumbnailImageURL: newsArticle.ThumbnailImageURL,
-		PublishDate:       newsArticle.PublishDate,
+		PublishDate:       publishDate,
+		LastUpdateDate:    newsArticle.LastUpdateDate,
 		IsPublished:       isPublished,
 		CreateAt:          time.Now(),
 	}
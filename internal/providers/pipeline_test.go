@@ -3,39 +3,514 @@ package providers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"reflect"
+	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
 	"github.com/patriciabonaldy/sports-news/internal"
 	"github.com/patriciabonaldy/sports-news/internal/platform/genericClient"
 	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/memory"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/storagetest"
+	"github.com/patriciabonaldy/sports-news/internal/platform/syncer/incrowd"
 )
 
+func Test_pipeLine_Process(t *testing.T) {
+	repository := memory.NewStorage()
+	batch := mockNewsBatch()
+	tests := []struct {
+		name   string
+		client genericClient.Client
+		want   RunStats
+	}{
+		{
+			name:   "new article",
+			client: &mockClient{body: mockNewsArticleInformation("Pontus explains", "2022-06-15 08:00:21")},
+			want:   RunStats{Inserted: 1},
+		},
+		{
+			name:   "same article",
+			client: &mockClient{body: mockNewsArticleInformation("Pontus explains", "2022-06-15 08:00:21")},
+			want:   RunStats{Unchanged: 1},
+		},
+		{
+			name:   "updated article",
+			client: &mockClient{body: mockNewsArticleInformation("Pontus Jansson explains", "2022-06-16 10:00:00")},
+			want:   RunStats{Updated: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPipeLine(repository, tt.client, config.Pipeline{}, logger.New())
+			got := p.Process(context.Background(), batch).Stats
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	article, err := repository.GetArticleByID(context.Background(), "brentford-641838")
+	assert.NoError(t, err)
+	assert.Equal(t, "Pontus Jansson explains", article.Title)
+	assert.Equal(t, []string{"Players", "Interviews"}, article.Taxonomies)
+	assert.Equal(t, []string{"https://www.brentfordfc.com/a.jpg", "https://www.brentfordfc.com/b.jpg"}, article.GalleryImageURLs)
+	assert.Equal(t, time.Date(2022, 6, 15, 8, 0, 0, 0, time.UTC), article.PublishDate)
+}
+
+func Test_pipeLine_Process_articles(t *testing.T) {
+	repository := memory.NewStorage()
+	article := internal.ArticleNews{
+		NewsID:         "arsenal-1",
+		ClubName:       "Arsenal News",
+		Title:          "Derby preview",
+		PublishDate:    time.Date(2024, 9, 14, 9, 30, 0, 0, time.UTC),
+		LastUpdateDate: "2024-09-14 09:30:00",
+		IsPublished:    true,
+	}
+	batch := NewsBatch{Provider: "arsenal", Articles: []internal.ArticleNews{article}}
+
+	p := NewPipeLine(repository, &mockClient{wantError: true}, config.Pipeline{}, logger.New())
+	assert.Equal(t, RunStats{Inserted: 1}, p.Process(context.Background(), batch).Stats)
+	assert.Equal(t, RunStats{Unchanged: 1}, p.Process(context.Background(), batch).Stats)
+
+	got, err := repository.GetArticleByID(context.Background(), "arsenal-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Derby preview", got.Title)
+}
+
+func Test_pipeLine_Process_articlesFailed(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewStorage()
+	article := internal.ArticleNews{
+		NewsID:      "arsenal-1",
+		ClubName:    "Arsenal News",
+		Title:       "Derby preview",
+		PublishDate: time.Date(2024, 9, 14, 9, 30, 0, 0, time.UTC),
+		IsPublished: true,
+	}
+	batch := NewsBatch{Provider: "arsenal", Articles: []internal.ArticleNews{article}}
+
+	failing := NewPipeLine(&storagetest.FailingStorage{Storage: repository, Err: errors.New("connection refused")},
+		&mockClient{wantError: true}, config.Pipeline{}, logger.New())
+	report := failing.Process(ctx, batch)
+	assert.Equal(t, RunStats{Failed: 1}, report.Stats)
+	require.Len(t, report.Failures(), 1)
+	assert.Equal(t, StageStore, report.Failures()[0].Stage)
+
+	deadLetter, err := repository.GetDeadLetter(ctx, "arsenal", "arsenal-1")
+	require.NoError(t, err)
+	assert.Equal(t, string(StageStore), deadLetter.Stage)
+	assert.True(t, deadLetter.IsPublished)
+	assert.Empty(t, deadLetter.ArticleURL)
+
+	// the feed has no detail to fetch again, the next sync stores it
+	assert.ErrorIs(t, failing.Retry(ctx, *deadLetter), errNotRefetchable)
+
+	p := NewPipeLine(repository, &mockClient{wantError: true}, config.Pipeline{}, logger.New())
+	assert.Equal(t, RunStats{Inserted: 1}, p.Process(ctx, batch).Stats)
+	_, err = repository.GetDeadLetter(ctx, "arsenal", "arsenal-1")
+	assert.ErrorIs(t, err, internal.ErrDeadLetterNotFound)
+}
+
+func Test_pipeLine_Process_sharedID(t *testing.T) {
+	repository := memory.NewStorage()
+	brentford := mockNewsBatch()
+	other := mockNewsBatch()
+	other.Provider = "millwall"
+
+	p := NewPipeLine(repository, &mockClient{body: mockNewsArticleInformation("Pontus explains", "2022-06-15 08:00:21")},
+		config.Pipeline{}, logger.New())
+	assert.Equal(t, RunStats{Inserted: 1}, p.Process(context.Background(), brentford).Stats)
+	// the clubs number their articles on their own
+	assert.Equal(t, RunStats{Inserted: 1}, p.Process(context.Background(), other).Stats)
+	assert.Equal(t, RunStats{Unchanged: 1}, p.Process(context.Background(), brentford).Stats)
+
+	for _, provider := range []string{"brentford", "millwall"} {
+		got, err := repository.GetArticleByID(context.Background(), provider+"-641838")
+		require.NoError(t, err)
+		assert.Equal(t, provider, got.Provider)
+	}
+}
+
+func Test_pipeLine_Process_withdraw(t *testing.T) {
+	repository := memory.NewStorage()
+	article := func(id string, day int) internal.ArticleNews {
+		return internal.ArticleNews{
+			NewsID:         id,
+			ClubName:       "Arsenal News",
+			Title:          "Article " + id,
+			PublishDate:    time.Date(2024, 9, day, 9, 30, 0, 0, time.UTC),
+			LastUpdateDate: "2024-09-14 09:30:00",
+			IsPublished:    true,
+		}
+	}
+	p := NewPipeLine(repository, &mockClient{wantError: true}, config.Pipeline{}, logger.New())
+	ctx := context.Background()
+
+	batch := NewsBatch{Provider: "arsenal", Articles: []internal.ArticleNews{
+		article("arsenal-0", 1), article("arsenal-1", 10), article("arsenal-2", 12), article("arsenal-3", 14),
+	}}
+	assert.Equal(t, RunStats{Inserted: 4}, p.Process(ctx, batch).Stats)
+
+	// arsenal-2 was pulled, arsenal-0 only dropped out of the list
+	batch.Articles = []internal.ArticleNews{article("arsenal-1", 10), article("arsenal-3", 14)}
+	assert.Equal(t, RunStats{Unchanged: 2, Deleted: 1}, p.Process(ctx, batch).Stats)
+
+	got, err := repository.GetArticleByID(ctx, "arsenal-2")
+	assert.NoError(t, err)
+	assert.True(t, got.IsDeleted())
+	got, err = repository.GetArticleByID(ctx, "arsenal-0")
+	assert.NoError(t, err)
+	assert.False(t, got.IsDeleted())
+
+	// an article listed again is restored
+	batch.Articles = append(batch.Articles, article("arsenal-2", 12))
+	assert.Equal(t, RunStats{Unchanged: 2, Updated: 1}, p.Process(ctx, batch).Stats)
+	got, err = repository.GetArticleByID(ctx, "arsenal-2")
+	assert.NoError(t, err)
+	assert.False(t, got.IsDeleted())
+	assert.Equal(t, "arsenal", got.Provider)
+}
+
+func Test_pipeLine_Process_unpublished(t *testing.T) {
+	repository := memory.NewStorage()
+	batch := mockNewsBatch()
+	batch.Items[0].PublishDate = "2022-06-15 08:00:00"
+	client := &mockClient{body: mockNewsArticleInformation("Pontus explains", "2022-06-15 08:00:21")}
+	p := NewPipeLine(repository, client, config.Pipeline{}, logger.New())
+
+	assert.Equal(t, RunStats{Inserted: 1}, p.Process(context.Background(), batch).Stats)
+
+	// the list flags the article before its detail does
+	batch.Items[0].IsPublished = "False"
+	assert.Equal(t, RunStats{Updated: 1}, p.Process(context.Background(), batch).Stats)
+	assert.Equal(t, RunStats{Unchanged: 1}, p.Process(context.Background(), batch).Stats)
+
+	got, err := repository.GetArticleByID(context.Background(), "brentford-641838")
+	assert.NoError(t, err)
+	assert.False(t, got.IsPublished)
+}
+
+func Test_pipeLine_Process_workers(t *testing.T) {
+	batch := mockNewsBatch()
+	batch.Items = nil
+	for i := 0; i < 20; i++ {
+		item := mockNewsletterNewsItem()
+		item.NewsArticleID = strconv.Itoa(i)
+		batch.Items = append(batch.Items, item)
+	}
+
+	tests := []struct {
+		name   string
+		client *concurrentClient
+		want   Progress
+	}{
+		{
+			name:   "stored",
+			client: &concurrentClient{body: mockNewsArticleInformation("Pontus explains", "2022-06-15 08:00:21")},
+			want:   Progress{Done: 20},
+		},
+		{
+			name:   "fetch failed",
+			client: &concurrentClient{wantError: true},
+			want:   Progress{Failed: 20},
+		},
+		{
+			name:   "parse failed",
+			client: &concurrentClient{body: `<NewsArticleInformation`},
+			want:   Progress{Failed: 20},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPipeLine(memory.NewStorage(), tt.client, config.Pipeline{Workers: 3, QueueSize: 2}, logger.New())
+			p.Process(context.Background(), batch)
+
+			assert.Equal(t, tt.want, p.Progress())
+			assert.Equal(t, int64(20), tt.client.calls)
+			assert.LessOrEqual(t, tt.client.max, int64(3))
+		})
+	}
+}
+
+func Test_pipeLine_Process_report(t *testing.T) {
+	storeErr := errors.New("connection refused")
+	batch := mockNewsBatch()
+	second := mockNewsletterNewsItem()
+	second.NewsArticleID = "641839"
+	batch.Items = append(batch.Items, second)
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		client     genericClient.Client
+		repository func() internal.Storage
+		wantStage  Stage
+		wantStats  RunStats
+		wantErr    error
+	}{
+		{
+			name:       "stored",
+			ctx:        context.Background(),
+			client:     &mockClient{body: mockNewsArticleInformation("Pontus explains", "2022-06-15 08:00:21")},
+			repository: func() internal.Storage { return memory.NewStorage() },
+			wantStage:  StageStore,
+			// both details are the same article
+			wantStats: RunStats{Inserted: 1, Unchanged: 1},
+		},
+		{
+			name:       "fetch failed",
+			ctx:        context.Background(),
+			client:     &mockClient{wantError: true},
+			repository: func() internal.Storage { return memory.NewStorage() },
+			wantStage:  StageFetch,
+			wantStats:  RunStats{Failed: 2},
+		},
+		{
+			name:       "parse failed",
+			ctx:        context.Background(),
+			client:     &mockClient{body: `<NewsArticleInformation`},
+			repository: func() internal.Storage { return memory.NewStorage() },
+			wantStage:  StageParse,
+			wantStats:  RunStats{Failed: 2},
+		},
+		{
+			name:   "store failed",
+			ctx:    context.Background(),
+			client: &mockClient{body: mockNewsArticleInformation("Pontus explains", "2022-06-15 08:00:21")},
+			repository: func() internal.Storage {
+				return &storagetest.FailingStorage{Storage: memory.NewStorage(), Err: storeErr}
+			},
+			wantStage: StageStore,
+			wantStats: RunStats{Failed: 2},
+		},
+		{
+			name:       "canceled",
+			ctx:        canceled,
+			client:     &mockClient{body: mockNewsArticleInformation("Pontus explains", "2022-06-15 08:00:21")},
+			repository: func() internal.Storage { return memory.NewStorage() },
+			wantStage:  StageFetch,
+			wantStats:  RunStats{Canceled: 2},
+			wantErr:    context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := tt.repository()
+			p := NewPipeLine(repository, tt.client, config.Pipeline{Workers: 2}, logger.New())
+			got := p.Process(tt.ctx, batch)
+
+			assert.Equal(t, "brentford", got.Provider)
+			assert.Equal(t, tt.wantStats, got.Stats)
+			assert.ErrorIs(t, got.Err, tt.wantErr)
+			require.Len(t, got.Items, 2)
+			assert.ElementsMatch(t, []string{"641838", "641839"}, []string{got.Items[0].ID, got.Items[1].ID})
+			for _, item := range got.Items {
+				assert.Equal(t, tt.wantStage, item.Stage)
+				if tt.wantStats.Failed > 0 {
+					assert.Equal(t, OutcomeFailed, item.Outcome)
+					assert.Error(t, item.Err)
+				}
+			}
+
+			assert.Len(t, got.Failures(), tt.wantStats.Failed)
+			failed := int64(tt.wantStats.Failed + tt.wantStats.Canceled)
+			assert.Equal(t, Progress{Done: 2 - failed, Failed: failed}, p.Progress())
+
+			// every failure, whatever its stage, can be retried later
+			deadLetters, err := repository.GetDeadLetters(context.Background())
+			require.NoError(t, err)
+			require.Len(t, deadLetters, tt.wantStats.Failed)
+			for _, deadLetter := range deadLetters {
+				assert.Equal(t, string(tt.wantStage), deadLetter.Stage)
+			}
+		})
+	}
+}
+
+func Test_pipeLine_Process_cancel(t *testing.T) {
+	batch := mockNewsBatch()
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		item := mockNewsletterNewsItem()
+		item.NewsArticleID = id
+		batch.Items = append(batch.Items, item)
+	}
+
+	client := &blockingClient{started: make(chan struct{}, len(batch.Items))}
+	p := NewPipeLine(memory.NewStorage(), client, config.Pipeline{Workers: 2}, logger.New())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan RunReport)
+	go func() {
+		done <- p.Process(ctx, batch)
+	}()
+
+	<-client.started
+	cancel()
+
+	select {
+	case got := <-done:
+		assert.ErrorIs(t, got.Err, context.Canceled)
+		assert.Equal(t, RunStats{Canceled: 6}, got.Stats)
+		assert.Equal(t, Progress{Failed: 6}, p.Progress())
+	case <-time.After(5 * time.Second):
+		t.Fatal("Process did not return after the context was canceled")
+	}
+}
+
+func Test_pipeLine_Process_ratePerHost(t *testing.T) {
+	batch := mockNewsBatch()
+	for _, id := range []string{"1", "2", "3"} {
+		item := mockNewsletterNewsItem()
+		item.NewsArticleID = id
+		batch.Items = append(batch.Items, item)
+	}
+
+	client := &concurrentClient{body: mockNewsArticleInformation("Pontus explains", "2022-06-15 08:00:21")}
+	p := NewPipeLine(memory.NewStorage(), client, config.Pipeline{Workers: 4, RatePerHost: 20}, logger.New())
+
+	// the first request is sent at once, the other three 50ms apart
+	start := time.Now()
+	p.Process(context.Background(), batch)
+	assert.GreaterOrEqual(t, time.Since(start), 140*time.Millisecond)
+	assert.Equal(t, Progress{Done: 4}, p.Progress())
+}
+
+func Test_pipeLine_Process_retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int64
+		err          error
+		wantStats    RunStats
+		wantAttempts int
+		wantDead     bool
+	}{
+		{
+			name:         "first attempt",
+			wantStats:    RunStats{Inserted: 1},
+			wantAttempts: 1,
+		},
+		{
+			name:         "stored on a retry",
+			failures:     2,
+			wantStats:    RunStats{Inserted: 1},
+			wantAttempts: 3,
+		},
+		{
+			name:         "attempts run out",
+			failures:     5,
+			wantStats:    RunStats{Failed: 1},
+			wantAttempts: 3,
+			wantDead:     true,
+		},
+		{
+			name:         "article not found",
+			failures:     5,
+			err:          genericClient.ErrNotFound,
+			wantStats:    RunStats{Failed: 1},
+			wantAttempts: 1,
+			wantDead:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := memory.NewStorage()
+			client := &flakyClient{
+				mockClient: mockClient{body: mockNewsArticleInformation("Pontus explains", "2022-06-15 08:00:21")},
+				failures:   tt.failures,
+				err:        tt.err,
+			}
+			p := NewPipeLine(repository, client, config.Pipeline{Attempts: 3, BackoffMillis: 1}, logger.New())
+
+			got := p.Process(context.Background(), mockNewsBatch())
+			assert.Equal(t, tt.wantStats, got.Stats)
+			require.Len(t, got.Items, 1)
+			assert.Equal(t, tt.wantAttempts, got.Items[0].Attempts)
+			assert.Equal(t, int64(tt.wantAttempts), atomic.LoadInt64(&client.calls))
+
+			deadLetters, err := repository.GetDeadLetters(context.Background())
+			require.NoError(t, err)
+			if !tt.wantDead {
+				assert.Empty(t, deadLetters)
+				return
+			}
+
+			require.Len(t, deadLetters, 1)
+			assert.Equal(t, "641838", deadLetters[0].ArticleID)
+			assert.Equal(t, "brentford", deadLetters[0].Provider)
+			assert.Equal(t, mockNewsBatch().ArticleURL, deadLetters[0].ArticleURL)
+			assert.True(t, deadLetters[0].IsPublished)
+			assert.Equal(t, string(StageFetch), deadLetters[0].Stage)
+			assert.Equal(t, tt.wantAttempts, deadLetters[0].Attempts)
+		})
+	}
+}
+
+func Test_pipeLine_Retry(t *testing.T) {
+	ctx := context.Background()
+	repository := memory.NewStorage()
+	failing := NewPipeLine(repository, &mockClient{wantError: true}, config.Pipeline{}, logger.New())
+	failing.Process(ctx, mockNewsBatch())
+
+	deadLetter, err := repository.GetDeadLetter(ctx, "brentford", "641838")
+	require.NoError(t, err)
+
+	// it fails again, the dead letter is kept
+	assert.Error(t, failing.Retry(ctx, *deadLetter))
+	_, err = repository.GetDeadLetter(ctx, "brentford", "641838")
+	assert.NoError(t, err)
+
+	p := NewPipeLine(repository, &mockClient{body: mockNewsArticleInformation("Pontus explains", "2022-06-15 08:00:21")},
+		config.Pipeline{}, logger.New())
+	require.NoError(t, p.Retry(ctx, *deadLetter))
+
+	article, err := repository.GetArticleByID(ctx, "brentford-641838")
+	require.NoError(t, err)
+	assert.Equal(t, "brentford", article.Provider)
+	_, err = repository.GetDeadLetter(ctx, "brentford", "641838")
+	assert.ErrorIs(t, err, internal.ErrDeadLetterNotFound)
+}
+
+func Test_pipeLine_backoffOf(t *testing.T) {
+	p := &pipeLine{backoff: 100 * time.Millisecond}
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 40: maxBackoff} {
+		got := p.backoffOf(attempt)
+		assert.GreaterOrEqual(t, got, want/2)
+		assert.LessOrEqual(t, got, want)
+	}
+
+	assert.Zero(t, (&pipeLine{}).backoffOf(1))
+}
+
 func Test_pipeLine_taskFetch(t *testing.T) {
 	type fields struct {
 		repository internal.Storage
 		client     genericClient.Client
 	}
 	tests := []struct {
-		name       string
-		fields     fields
-		expectData bool
-		want       []byte
+		name    string
+		fields  fields
+		want    []byte
+		wantErr bool
 	}{
 		{
 			name: "want error fetch data",
 			fields: fields{
 				client: &mockClient{wantError: true},
 			},
+			wantErr: true,
 		},
 		{
 			name: "success",
 			fields: fields{
 				client: &mockClient{},
 			},
-			expectData: true,
 			want: []byte(`<NewListInformation>
 <ClubName>Brentford</ClubName>
 <ClubWebsiteURL>https://www.brentfordfc.com</ClubWebsiteURL>
@@ -57,56 +532,61 @@ func Test_pipeLine_taskFetch(t *testing.T) {
 		`),
 		},
 	}
-	data := []NewsletterNewsItem{mockNewsletterNewsItem()}
+	data := []incrowd.NewsletterNewsItem{mockNewsletterNewsItem()}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &pipeLine{
 				repository: tt.fields.repository,
 				client:     tt.fields.client,
+				limiter:    newHostLimiter(0),
+				workers:    1,
 				log:        logger.New(),
 			}
 
-			ch := p.taskFetch(context.Background(), data)
+			ch := p.taskFetch(context.Background(), legacyArticleURL, data)
 
-			var got string
-			if tt.expectData {
-				got = string(<-ch)
+			got := <-ch
+			assert.Equal(t, "641838", got.id)
+			assert.Equal(t, StageFetch, got.stage)
+			if tt.wantErr {
+				assert.Error(t, got.err)
+			} else {
+				assert.NoError(t, got.err)
 			}
 
-			want := string(tt.want)
-			if !reflect.DeepEqual(got, want) {
-				t.Errorf("taskFetch() = %v, want %v", got, tt.want)
+			if !reflect.DeepEqual(string(got.body), string(tt.want)) {
+				t.Errorf("taskFetch() = %v, want %v", string(got.body), string(tt.want))
 			}
+
+			_, open := <-ch
+			assert.False(t, open)
 		})
 	}
 }
 
 func Test_pipeLine_taskParse(t *testing.T) {
+	fetchErr := errors.New("unknown error")
 	tests := []struct {
-		name       string
-		ch1        func() chan []byte
-		want       func() chan NewsArticleInformation
-		expectData bool
+		name      string
+		in        item
+		want      incrowd.NewsArticle
+		wantStage Stage
+		wantErr   error
 	}{
 		{
-			name: "want error unmarshal",
-			ch1: func() chan []byte {
-				ch2 := make(chan []byte)
-				go func() {
-					ch2 <- []byte(`<>`)
-				}()
-				return ch2
-			},
-			want: func() chan NewsArticleInformation {
-				return make(chan NewsArticleInformation)
-			},
+			name:      "want error unmarshal",
+			in:        item{id: "173860", stage: StageFetch, body: []byte(`<>`)},
+			wantStage: StageParse,
+		},
+		{
+			name:      "failed fetch is passed on",
+			in:        item{id: "173860", stage: StageFetch, err: fetchErr},
+			wantStage: StageFetch,
+			wantErr:   fetchErr,
 		},
 		{
 			name: "success",
-			ch1: func() chan []byte {
-				ch2 := make(chan []byte)
-				go func() {
-					ch2 <- []byte(`<NewsArticleInformation>
+			in: item{id: "173860", stage: StageFetch, body: []byte(`<NewsArticleInformation>
 									<ClubName>Brentford</ClubName>
 									<ClubWebsiteURL>https://www.brentfordfc.com</ClubWebsiteURL>
 									<NewsArticle>
@@ -126,31 +606,18 @@ func Test_pipeLine_taskParse(t *testing.T) {
 									<IsPublished>True</IsPublished>
 									</NewsArticle>
 									</NewsArticleInformation>
-											`)
-				}()
-				return ch2
+											`)},
+			want: incrowd.NewsArticle{
+				Text:           "\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t",
+				ArticleURL:     "https://www.brentfordfc.com/news/2017/june/be-there-in-201718/",
+				NewsArticleID:  "173860",
+				PublishDate:    "2017-06-05 10:33:39",
+				Taxonomies:     "Ticket News",
+				Title:          "Be There for our 2017/18 season",
+				LastUpdateDate: "2019-09-02 03:36:54",
+				IsPublished:    "True",
 			},
-			want: func() chan NewsArticleInformation {
-				ch2 := make(chan NewsArticleInformation)
-				go func() {
-					ch2 <- NewsArticleInformation{
-						ClubName:       "Brentford",
-						ClubWebsiteURL: "https://www.brentfordfc.com",
-						NewsArticle: NewsArticle{
-							Text:           "\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t\n\t\t\t\t\t\t\t\t\t",
-							ArticleURL:     "https://www.brentfordfc.com/news/2017/june/be-there-in-201718/",
-							NewsArticleID:  "173860",
-							PublishDate:    "2017-06-05 10:33:39",
-							Taxonomies:     "Ticket News",
-							Title:          "Be There for our 2017/18 season",
-							LastUpdateDate: "2019-09-02 03:36:54",
-							IsPublished:    "True",
-						},
-					}
-				}()
-				return ch2
-			},
-			expectData: true,
+			wantStage: StageParse,
 		},
 	}
 	for _, tt := range tests {
@@ -158,25 +625,30 @@ func Test_pipeLine_taskParse(t *testing.T) {
 			p := &pipeLine{
 				log: logger.New(),
 			}
-			ch := p.taskParse(tt.ch1())
-			chWant := tt.want()
-
-			var got string
-			var want string
-			if tt.expectData {
-				aiGot := <-ch
-				bGot, err := json.Marshal(aiGot.NewsArticle)
-				assert.NoError(t, err)
-				got = string(bGot)
-
-				aiWant := <-chWant
-				bWant, err := json.Marshal(aiWant.NewsArticle)
-				assert.NoError(t, err)
-				want = string(bWant)
+			ch1 := make(chan item, 1)
+			ch1 <- tt.in
+			close(ch1)
+
+			got := <-p.taskParse(ch1)
+			assert.Equal(t, tt.in.id, got.id)
+			assert.Equal(t, tt.wantStage, got.stage)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, got.err, tt.wantErr)
+				return
+			}
+
+			if tt.want.NewsArticleID == "" {
+				assert.Error(t, got.err)
+				return
 			}
 
-			if !reflect.DeepEqual(got, want) {
-				t.Errorf("taskParse() got= %v\n, want %v", got, want)
+			require.NoError(t, got.err)
+			bGot, err := json.Marshal(got.article.NewsArticle)
+			assert.NoError(t, err)
+			bWant, err := json.Marshal(tt.want)
+			assert.NoError(t, err)
+			if !reflect.DeepEqual(string(bGot), string(bWant)) {
+				t.Errorf("taskParse() got= %v\n, want %v", string(bGot), string(bWant))
 			}
 		})
 	}
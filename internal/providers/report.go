@@ -0,0 +1,107 @@
+package providers
+
+import (
+	"time"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+)
+
+// Stage is a step of the pipeline, items from the list of a provider are
+// fetched, parsed and stored while complete articles are only stored.
+type Stage string
+
+const (
+	StageFetch Stage = "fetch"
+	StageParse Stage = "parse"
+	StageStore Stage = "store"
+)
+
+// Outcome is how the processing of an item ended.
+type Outcome string
+
+const (
+	OutcomeInserted  Outcome = "inserted"
+	OutcomeUpdated   Outcome = "updated"
+	OutcomeUnchanged Outcome = "unchanged"
+	OutcomeFailed    Outcome = "failed"
+	// OutcomeCanceled is the outcome of the items left when the context
+	// of the run was done.
+	OutcomeCanceled Outcome = "canceled"
+)
+
+// RunStats counts the outcome of the items of a Process run, and the
+// stored articles it found pulled from the list.
+type RunStats struct {
+	Inserted    int
+	Updated     int
+	Unchanged   int
+	Failed      int
+	Canceled    int
+	Deleted     int
+	Unpublished int
+}
+
+// RunReport is the outcome of a Process run. Err is the error of the
+// context when the run was canceled, the pulled articles are not
+// withdrawn then.
+type RunReport struct {
+	Provider string
+	Stats    RunStats
+	Items    []ItemReport
+	Started  time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// ItemReport is the outcome of an item of the batch. Stage is the last
+// stage it reached, the one that failed for failed items, Attempts the
+// number of fetches of its detail and Duration is the time it took from
+// the start of its first stage.
+type ItemReport struct {
+	ID       string
+	Stage    Stage
+	Outcome  Outcome
+	Err      error
+	Attempts int
+	Duration time.Duration
+}
+
+// Failures returns the reports of the failed items.
+func (r RunReport) Failures() []ItemReport {
+	var failures []ItemReport
+	for _, item := range r.Items {
+		if item.Outcome == OutcomeFailed {
+			failures = append(failures, item)
+		}
+	}
+
+	return failures
+}
+
+// add records the report of an item and counts its outcome.
+func (r *RunReport) add(item ItemReport) {
+	r.Items = append(r.Items, item)
+	switch item.Outcome {
+	case OutcomeInserted:
+		r.Stats.Inserted++
+	case OutcomeUpdated:
+		r.Stats.Updated++
+	case OutcomeUnchanged:
+		r.Stats.Unchanged++
+	case OutcomeCanceled:
+		r.Stats.Canceled++
+	default:
+		r.Stats.Failed++
+	}
+}
+
+func outcomeOf(result internal.UpsertResult) Outcome {
+	switch result {
+	case internal.UpsertInserted:
+		return OutcomeInserted
+	case internal.UpsertUpdated:
+		return OutcomeUpdated
+	default:
+		return OutcomeUnchanged
+	}
+}
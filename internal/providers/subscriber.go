@@ -0,0 +1,146 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/pubsub"
+)
+
+type service struct {
+	pipeline   Pipeline
+	subscriber pubsub.Subscriber
+	log        logger.Logger
+
+	// cancel cancels the batch in flight and done is closed once the
+	// subscriber returned.
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// legacyArticleURL is the detail URL of the messages published before
+// the batches carried their provider.
+const legacyArticleURL = "https://www.brentfordfc.com/api/incrowd/getnewsarticleinformation?id=%s"
+
+// NewNewsSubscriber returns the subscriber that runs the pipeline for
+// every batch of articles published by the provider syncers.
+func NewNewsSubscriber(pipeline Pipeline, subscriber pubsub.Subscriber, log logger.Logger) *service {
+	return &service{
+		pipeline:   pipeline,
+		subscriber: subscriber,
+		log:        log,
+	}
+}
+
+// Start consumes the batches in the background until Stop is called or
+// the context is done.
+func (s *service) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		// the batch in flight runs on after the subscriber stops
+		// consuming, until Stop cancels it
+		s.subscriber.Subscriber(ctx, s.callBack)
+	}()
+}
+
+// Stop stops consuming and waits for the batch in flight to finish. When
+// the context is done first the items left are canceled, and it returns
+// the error of the context once the pipeline returned.
+func (s *service) Stop(ctx context.Context) error {
+	s.subscriber.Stop()
+	defer s.cancel()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-s.done
+		return ctx.Err()
+	}
+}
+
+// callBack runs the pipeline for the batch of the message. Messages that
+// can not be decoded fail permanently, and batches with articles that
+// could not be stored fail so that the subscriber retries them, storing
+// the same articles again is harmless.
+func (s *service) callBack(ctx context.Context, message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return pubsub.Permanent(err)
+	}
+
+	var msg pubsub.Message
+	err = json.Unmarshal(data, &msg)
+	if err != nil {
+		return pubsub.Permanent(err)
+	}
+
+	batch, err := decodeBatch(msg.RawData)
+	if err != nil {
+		return pubsub.Permanent(err)
+	}
+
+	report := s.pipeline.Process(ctx, batch)
+	stats := report.Stats
+	s.log.Infof("%s sync process finished in %s: inserted=%d updated=%d unchanged=%d failed=%d canceled=%d deleted=%d unpublished=%d",
+		batch.Provider, report.Duration, stats.Inserted, stats.Updated, stats.Unchanged, stats.Failed, stats.Canceled,
+		stats.Deleted, stats.Unpublished)
+	for _, failure := range report.Failures() {
+		s.log.Infof("%s article %s failed at %s after %s: %s",
+			batch.Provider, failure.ID, failure.Stage, failure.Duration, failure.Err)
+	}
+
+	progress := s.pipeline.Progress()
+	s.log.Infof("pipeline items: queued=%d in_flight=%d done=%d failed=%d",
+		progress.Queued, progress.InFlight, progress.Done, progress.Failed)
+
+	if report.Err != nil {
+		return fmt.Errorf("%s sync process canceled: %w", batch.Provider, report.Err)
+	}
+
+	var notStored int
+	for _, failure := range report.Failures() {
+		if failure.Stage == StageStore {
+			notStored++
+		}
+	}
+
+	if notStored > 0 {
+		return fmt.Errorf("%s sync process: %d articles could not be stored", batch.Provider, notStored)
+	}
+
+	return nil
+}
+
+// decodeBatch also accepts the plain list of articles published
+// before the provider registry existed, which were all from Brentford.
+func decodeBatch(data []byte) (NewsBatch, error) {
+	var batch NewsBatch
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		batch.Provider = "brentford"
+		batch.ArticleURL = legacyArticleURL
+		err := json.Unmarshal(data, &batch.Items)
+
+		return batch, err
+	}
+
+	var msg batchMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return batch, err
+	}
+
+	batch = msg.toBatch()
+	if len(batch.Items) > 0 && batch.ArticleURL == "" {
+		return batch, fmt.Errorf("batch of provider %s without article_url", batch.Provider)
+	}
+
+	return batch, nil
+}
@@ -0,0 +1,170 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/pubsub"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/memory"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/storagetest"
+	"github.com/patriciabonaldy/sports-news/internal/platform/syncer/incrowd"
+)
+
+func Test_service_callBack(t *testing.T) {
+	batch, err := json.Marshal(mockBatchMessage())
+	require.NoError(t, err)
+	client := &mockClient{body: mockNewsArticleInformation("Pontus explains", "2022-06-15 08:00:21")}
+
+	tests := []struct {
+		name          string
+		data          []byte
+		repository    func() internal.Storage
+		wantErr       bool
+		wantPermanent bool
+	}{
+		{
+			name:       "stored",
+			data:       batch,
+			repository: func() internal.Storage { return memory.NewStorage() },
+		},
+		{
+			name:          "invalid batch",
+			data:          []byte(`{"provider":"arsenal","items":[{"NewsArticleID":"1"}]}`),
+			repository:    func() internal.Storage { return memory.NewStorage() },
+			wantErr:       true,
+			wantPermanent: true,
+		},
+		{
+			name: "articles not stored",
+			data: batch,
+			repository: func() internal.Storage {
+				return &storagetest.FailingStorage{Storage: memory.NewStorage(), Err: errors.New("connection refused")}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := NewPipeLine(tt.repository(), client, config.Pipeline{}, logger.New())
+			s := NewNewsSubscriber(pipeline, nil, logger.New())
+
+			err := s.callBack(context.Background(), pubsub.Message{EventID: "1", RawData: tt.data})
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Equal(t, tt.wantPermanent, pubsub.IsPermanent(err))
+		})
+	}
+}
+
+func Test_service_Stop(t *testing.T) {
+	tests := []struct {
+		name      string
+		timeout   time.Duration
+		release   bool
+		wantErr   error
+		wantStats Progress
+	}{
+		{name: "drains the batch in flight", timeout: 5 * time.Second, release: true, wantStats: Progress{Done: 1}},
+		{name: "cancels the batch after the timeout", timeout: 50 * time.Millisecond, wantErr: context.DeadlineExceeded, wantStats: Progress{Failed: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := pubsub.NewQueue()
+			client := &blockingClient{
+				mockClient: mockClient{body: mockNewsArticleInformation("Pontus explains", "2022-06-15 08:00:21")},
+				started:    make(chan struct{}, 1),
+				release:    make(chan struct{}),
+			}
+			pipeline := NewPipeLine(memory.NewStorage(), client, config.Pipeline{}, logger.New())
+			s := NewNewsSubscriber(pipeline, pubsub.NewSubscriber(queue, pubsub.RetryPolicy{}, nil, logger.New()), logger.New())
+			s.Start(context.Background())
+
+			data, err := json.Marshal(mockBatchMessage())
+			require.NoError(t, err)
+			require.NoError(t, queue.Publish(context.Background(), pubsub.Message{EventID: "1", RawData: data}))
+			<-client.started
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			if tt.release {
+				time.AfterFunc(10*time.Millisecond, func() { close(client.release) })
+			}
+
+			assert.ErrorIs(t, s.Stop(ctx), tt.wantErr)
+			assert.Equal(t, tt.wantStats, pipeline.Progress())
+		})
+	}
+}
+
+func Test_decodeBatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    NewsBatch
+		wantErr bool
+	}{
+		{
+			name: "batch",
+			data: `{"provider":"arsenal","article_url":"https://www.arsenal.com/news?id=%s","items":[{"NewsArticleID":"1"}]}`,
+			want: NewsBatch{
+				Provider:   "arsenal",
+				ArticleURL: "https://www.arsenal.com/news?id=%s",
+				Items:      []incrowd.NewsletterNewsItem{{NewsArticleID: "1"}},
+			},
+		},
+		{
+			name: "legacy list of articles",
+			data: ` [{"NewsArticleID":"1"}]`,
+			want: NewsBatch{
+				Provider:   "brentford",
+				ArticleURL: legacyArticleURL,
+				Items:      []incrowd.NewsletterNewsItem{{NewsArticleID: "1"}},
+			},
+		},
+		{
+			name: "batch of complete articles",
+			data: `{"provider":"arsenal","articles":[{"id":"arsenal-1","title":"Derby preview","body":{"blocks":[{"type":"paragraph","inlines":[{"type":"text","text":"Derby"}]}]}}]}`,
+			want: NewsBatch{
+				Provider: "arsenal",
+				Articles: []internal.ArticleNews{{
+					NewsID: "arsenal-1",
+					Title:  "Derby preview",
+					Body: internal.Body{Blocks: []internal.Block{{
+						Type:    internal.BlockParagraph,
+						Inlines: []internal.Inline{{Type: internal.InlineText, Text: "Derby"}},
+					}}},
+				}},
+			},
+		},
+		{
+			name:    "batch without article url",
+			data:    `{"provider":"arsenal","items":[{"NewsArticleID":"1"}]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeBatch([]byte(tt.data))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
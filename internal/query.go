@@ -0,0 +1,184 @@
+package internal
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPageLimit is the page size used when the client does not ask for one.
+	DefaultPageLimit = 20
+	// MaxPageLimit is the biggest page size a client can ask for.
+	MaxPageLimit = 100
+)
+
+// PublishDateLayout is the layout of the dates published by the
+// providers, of ArticleNews.LastUpdateDate and of the cursor keys.
+const PublishDateLayout = "2006-01-02 15:04:05"
+
+// ParsePublishDate parses a date in PublishDateLayout as UTC, an empty
+// value is the zero time.
+func ParsePublishDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	return time.ParseInLocation(PublishDateLayout, value, time.UTC)
+}
+
+// FormatPublishDate formats t in PublishDateLayout, the zero time is
+// an empty value.
+func FormatPublishDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(PublishDateLayout)
+}
+
+// SplitList splits the comma separated lists published by the
+// providers, like taxonomies or gallery images, dropping empty values.
+func SplitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+// SortField is an ArticleNews field that articles can be ordered by.
+type SortField string
+
+const (
+	SortByPublishDate SortField = "publish_date"
+	SortByCreateAt    SortField = "create_at"
+)
+
+// Sort is the order of a page of articles. Ties are broken by NewsID
+// so that the order is stable across pages.
+type Sort struct {
+	Field     SortField
+	Ascending bool
+}
+
+// DefaultSort returns the newest articles first.
+func DefaultSort() Sort {
+	return Sort{Field: SortByPublishDate}
+}
+
+// ParseSort parses values like "publish_date" or "-create_at".
+// A leading "-" means descending order, an empty value returns DefaultSort.
+func ParseSort(value string) (Sort, error) {
+	if value == "" {
+		return DefaultSort(), nil
+	}
+
+	sort := Sort{Ascending: true}
+	if strings.HasPrefix(value, "-") {
+		sort.Ascending = false
+		value = strings.TrimPrefix(value, "-")
+	}
+
+	switch SortField(value) {
+	case SortByPublishDate, SortByCreateAt:
+		sort.Field = SortField(value)
+	default:
+		return Sort{}, ErrInvalidSort
+	}
+
+	return sort, nil
+}
+
+func (s Sort) String() string {
+	if s.Ascending {
+		return string(s.Field)
+	}
+
+	return "-" + string(s.Field)
+}
+
+// ArticleFilter restricts the articles returned by the Storage.
+// Zero values are not applied.
+type ArticleFilter struct {
+	ClubName  string
+	Taxonomy  string
+	Published *bool
+	Deleted   *bool
+	// From and To bound the publish date, From is inclusive and To is exclusive.
+	From time.Time
+	To   time.Time
+}
+
+// ArticleQuery describes a page of articles to be read from the Storage.
+type ArticleQuery struct {
+	Filter ArticleFilter
+	Limit  int
+	Cursor string
+	Sort   Sort
+	// IncludeArchived lists the archived articles with the others.
+	IncludeArchived bool
+}
+
+// ArticlePage is a page of articles, Next is empty on the last page.
+type ArticlePage struct {
+	Articles []ArticleNews
+	Limit    int
+	Sort     Sort
+	Next     string
+}
+
+// Cursor is the position of the last article of a page.
+// Clients only see it as an opaque token.
+type Cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   string `json:"id"`
+}
+
+// Encode returns the opaque token of the cursor.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token returned by Cursor.Encode and checks
+// that it was generated for the given sort.
+func DecodeCursor(token string, sort Sort) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err = json.Unmarshal(data, &c); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	if c.Sort != sort.String() || c.ID == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+// SearchQuery describes a full-text search over title, subtitle,
+// teaser and body of the articles.
+type SearchQuery struct {
+	Text  string
+	Limit int
+}
+
+// SearchResult is an article matching a SearchQuery, best matches have
+// a higher Score. Highlights are HTML-escaped snippets of the matching
+// text where the search terms are wrapped in <em> tags.
+type SearchResult struct {
+	Article    ArticleNews
+	Score      float64
+	Highlights []string
+}
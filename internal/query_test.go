@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Sort
+		wantErr error
+	}{
+		{value: "", want: Sort{Field: SortByPublishDate}},
+		{value: "publish_date", want: Sort{Field: SortByPublishDate, Ascending: true}},
+		{value: "-create_at", want: Sort{Field: SortByCreateAt}},
+		{value: "title", wantErr: ErrInvalidSort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSort(tt.value)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	sort := Sort{Field: SortByCreateAt}
+	cursor := Cursor{Sort: sort.String(), Key: "1655280000", ID: "641838"}
+
+	got, err := DecodeCursor(cursor.Encode(), sort)
+	require.NoError(t, err)
+	assert.Equal(t, cursor, got)
+
+	_, err = DecodeCursor(cursor.Encode(), DefaultSort())
+	assert.Equal(t, ErrInvalidCursor, err)
+
+	_, err = DecodeCursor("%%%", sort)
+	assert.Equal(t, ErrInvalidCursor, err)
+}
+
+func TestParsePublishDate(t *testing.T) {
+	got, err := ParsePublishDate("2022-06-15 08:00:21")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2022, 6, 15, 8, 0, 21, 0, time.UTC), got)
+	assert.Equal(t, "2022-06-15 08:00:21", FormatPublishDate(got))
+
+	got, err = ParsePublishDate("")
+	assert.NoError(t, err)
+	assert.True(t, got.IsZero())
+	assert.Equal(t, "", FormatPublishDate(got))
+
+	_, err = ParsePublishDate("15/06/2022")
+	assert.Error(t, err)
+}
+
+func TestSplitList(t *testing.T) {
+	assert.Equal(t, []string{"Players", "International"}, SplitList(" Players,, International ,"))
+	assert.Nil(t, SplitList(""))
+}
@@ -0,0 +1,118 @@
+package retention
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/patriciabonaldy/sports-news/internal"
+)
+
+// Record is an exported article, one line of the export file. It is
+// decoupled from internal.ArticleNews so that changes of the domain do
+// not change the format of the files already exported.
+type Record struct {
+	ID                string         `json:"id"`
+	Provider          string         `json:"provider,omitempty"`
+	ClubName          string         `json:"club_name"`
+	ClubWebsiteURL    string         `json:"club_website_url,omitempty"`
+	ArticleURL        string         `json:"article_url,omitempty"`
+	Title             string         `json:"title"`
+	Subtitle          string         `json:"subtitle,omitempty"`
+	BodyText          string         `json:"body_text,omitempty"`
+	Body              *internal.Body `json:"body,omitempty"`
+	GalleryImageURLs  []string       `json:"gallery_image_urls,omitempty"`
+	VideoURL          string         `json:"video_url,omitempty"`
+	Taxonomies        []string       `json:"taxonomies,omitempty"`
+	TeaserText        string         `json:"teaser_text,omitempty"`
+	ThumbnailImageURL string         `json:"thumbnail_image_url,omitempty"`
+	PublishDate       time.Time      `json:"publish_date"`
+	LastUpdateDate    string         `json:"last_update_date,omitempty"`
+	IsPublished       bool           `json:"is_published"`
+	CreateAt          time.Time      `json:"create_at"`
+	DeletedAt         *time.Time     `json:"deleted_at,omitempty"`
+	ArchivedAt        time.Time      `json:"archived_at"`
+}
+
+// NewRecord returns the exported record of the article.
+func NewRecord(article internal.ArticleNews) Record {
+	r := Record{
+		ID:                article.NewsID,
+		Provider:          article.Provider,
+		ClubName:          article.ClubName,
+		ClubWebsiteURL:    article.ClubWebsiteURL,
+		ArticleURL:        article.ArticleURL,
+		Title:             article.Title,
+		Subtitle:          article.Subtitle,
+		BodyText:          article.BodyText,
+		GalleryImageURLs:  article.GalleryImageURLs,
+		VideoURL:          article.VideoURL,
+		Taxonomies:        article.Taxonomies,
+		TeaserText:        article.TeaserText,
+		ThumbnailImageURL: article.ThumbnailImageURL,
+		PublishDate:       article.PublishDate,
+		LastUpdateDate:    article.LastUpdateDate,
+		IsPublished:       article.IsPublished,
+		CreateAt:          article.CreateAt,
+		ArchivedAt:        article.ArchivedAt,
+	}
+	if !article.Body.IsEmpty() {
+		body := article.Body
+		r.Body = &body
+	}
+	if !article.DeletedAt.IsZero() {
+		deletedAt := article.DeletedAt
+		r.DeletedAt = &deletedAt
+	}
+
+	return r
+}
+
+// Export writes the articles to a gzip compressed JSONL file in dir, one
+// article per line, and returns its path. The file is named after at
+// and only appears once it is complete. Like the rest of the archive it
+// is readable by everyone.
+func Export(dir string, articles []internal.ArticleNews, at time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("articles-%s.jsonl.gz", at.UTC().Format("20060102T150405Z")))
+	tmp, err := os.CreateTemp(dir, ".articles-*.jsonl.gz")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err = tmp.Chmod(0o644); err != nil {
+		return "", err
+	}
+
+	zw := gzip.NewWriter(tmp)
+	w := bufio.NewWriter(zw)
+	encoder := json.NewEncoder(w)
+	for _, article := range articles {
+		if err = encoder.Encode(NewRecord(article)); err != nil {
+			return "", err
+		}
+	}
+
+	if err = w.Flush(); err != nil {
+		return "", err
+	}
+
+	if err = zw.Close(); err != nil {
+		return "", err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return "", err
+	}
+
+	return path, os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,93 @@
+package retention
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+)
+
+// Job archives the articles older than the retention period of their
+// club and, when an export directory is configured, exports them.
+type Job struct {
+	repository internal.Storage
+	retention  config.Retention
+	log        logger.Logger
+}
+
+// NewJob returns the retention job of the configured policy.
+func NewJob(repository internal.Storage, retention config.Retention, log logger.Logger) *Job {
+	return &Job{repository: repository, retention: retention, log: log}
+}
+
+// Run archives the articles expired at now and returns how many were
+// archived.
+func (j *Job) Run(ctx context.Context, now time.Time) (int, error) {
+	var archived []internal.ArticleNews
+	for _, query := range j.queries(now) {
+		articles, err := j.repository.Archive(ctx, query)
+		archived = append(archived, articles...)
+		if err != nil {
+			// the articles archived so far are exported anyway, the
+			// error of the archive is the one returned.
+			_ = j.export(archived, now)
+			return len(archived), err
+		}
+	}
+
+	if err := j.export(archived, now); err != nil {
+		return len(archived), err
+	}
+
+	return len(archived), nil
+}
+
+// queries returns an archive query per club with its own retention and
+// one for the other clubs when there is a default retention.
+func (j *Job) queries(now time.Time) []internal.ArchiveQuery {
+	clubs := make([]string, 0, len(j.retention.Clubs))
+	for club := range j.retention.Clubs {
+		clubs = append(clubs, club)
+	}
+	sort.Strings(clubs)
+
+	var queries []internal.ArchiveQuery
+	for _, club := range clubs {
+		if days := j.retention.Clubs[club]; days > 0 {
+			queries = append(queries, internal.ArchiveQuery{
+				ClubName: club,
+				Before:   now.AddDate(0, 0, -days),
+				At:       now,
+			})
+		}
+	}
+
+	if j.retention.Days > 0 {
+		queries = append(queries, internal.ArchiveQuery{
+			ExceptClubs: clubs,
+			Before:      now.AddDate(0, 0, -j.retention.Days),
+			At:          now,
+		})
+	}
+
+	return queries
+}
+
+func (j *Job) export(articles []internal.ArticleNews, now time.Time) error {
+	if j.retention.ExportDir == "" || len(articles) == 0 {
+		return nil
+	}
+
+	path, err := Export(j.retention.ExportDir, articles, now)
+	if err != nil {
+		j.log.Errorf("error export archived articles %s", err.Error())
+		return err
+	}
+
+	j.log.Infof("%d archived articles exported to %s", len(articles), path)
+
+	return nil
+}
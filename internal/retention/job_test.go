@@ -0,0 +1,129 @@
+package retention
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/patriciabonaldy/sports-news/cmd/bootstrap/config"
+	"github.com/patriciabonaldy/sports-news/internal"
+	"github.com/patriciabonaldy/sports-news/internal/platform/logger"
+	"github.com/patriciabonaldy/sports-news/internal/platform/storage/memory"
+)
+
+func TestJob_Run(t *testing.T) {
+	now := time.Date(2022, 12, 31, 3, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		retention config.Retention
+		want      []string
+	}{
+		{
+			name: "no retention",
+		},
+		{
+			name:      "per club",
+			retention: config.Retention{Clubs: map[string]int{"Brentford": 180}},
+			want:      []string{"brentford-old"},
+		},
+		{
+			name:      "default for the other clubs",
+			retention: config.Retention{Days: 30, Clubs: map[string]int{"Brentford": 365}},
+			want:      []string{"arsenal-old", "arsenal-recent"},
+		},
+		{
+			name:      "club kept forever",
+			retention: config.Retention{Days: 30, Clubs: map[string]int{"Brentford": 0}},
+			want:      []string{"arsenal-old", "arsenal-recent"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := mockRepository(t, now)
+			job := NewJob(repository, tt.retention, logger.New())
+
+			got, err := job.Run(context.Background(), now)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.want), got)
+
+			page, err := repository.GetArticlesPage(context.Background(), internal.ArticleQuery{
+				Limit:           internal.MaxPageLimit,
+				Sort:            internal.DefaultSort(),
+				IncludeArchived: true,
+			})
+			require.NoError(t, err)
+			var archived []string
+			for _, article := range page.Articles {
+				if article.IsArchived() {
+					archived = append(archived, article.NewsID)
+				}
+			}
+			assert.ElementsMatch(t, tt.want, archived)
+		})
+	}
+}
+
+func TestJob_Run_export(t *testing.T) {
+	now := time.Date(2022, 12, 31, 3, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	job := NewJob(mockRepository(t, now), config.Retention{Days: 180, ExportDir: dir}, logger.New())
+
+	got, err := job.Run(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got)
+
+	path := filepath.Join(dir, "articles-20221231T030000Z.jsonl.gz")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	zr, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	var ids []string
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		assert.Contains(t, scanner.Text(), `"archived_at":"2022-12-31T03:00:00Z"`)
+
+		var record Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		assert.Equal(t, now, record.ArchivedAt)
+		ids = append(ids, record.ID)
+	}
+	require.NoError(t, scanner.Err())
+	assert.ElementsMatch(t, []string{"brentford-old", "arsenal-old"}, ids)
+
+	// nothing is exported when nothing expired
+	got, err = job.Run(context.Background(), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, got)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func mockRepository(t *testing.T, now time.Time) *memory.Repository {
+	repository := memory.NewStorage()
+	for _, article := range []internal.ArticleNews{
+		{NewsID: "brentford-old", ClubName: "Brentford", PublishDate: now.AddDate(0, 0, -200)},
+		{NewsID: "brentford-recent", ClubName: "Brentford", PublishDate: now.AddDate(0, 0, -40)},
+		{NewsID: "arsenal-old", ClubName: "Arsenal", PublishDate: now.AddDate(0, 0, -200)},
+		{NewsID: "arsenal-recent", ClubName: "Arsenal", PublishDate: now.AddDate(0, 0, -40)},
+		{NewsID: "arsenal-undated", ClubName: "Arsenal"},
+	} {
+		require.NoError(t, repository.Save(context.Background(), article))
+	}
+
+	return repository
+}
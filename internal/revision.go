@@ -0,0 +1,35 @@
+package internal
+
+import "time"
+
+// Revision is a previous version of an article, kept when an update
+// replaced it. Revisions of an article are numbered from 1, the oldest.
+type Revision struct {
+	Number     int
+	Article    ArticleNews
+	ReplacedAt time.Time
+}
+
+// FieldChange is a field that differs between two versions of an article.
+type FieldChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// Diff returns the fields that changed from one version of the article to another.
+func Diff(from, to ArticleNews) []FieldChange {
+	changes := make([]FieldChange, 0)
+	toFields := to.contentFields()
+	for i, field := range from.contentFields() {
+		if field.value != toFields[i].value {
+			changes = append(changes, FieldChange{Field: field.name, From: field.value, To: toFields[i].value})
+		}
+	}
+
+	if from.LastUpdateDate != to.LastUpdateDate {
+		changes = append(changes, FieldChange{Field: "last_update_date", From: from.LastUpdateDate, To: to.LastUpdateDate})
+	}
+
+	return changes
+}
@@ -0,0 +1,22 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	from := ArticleNews{NewsID: "1", Title: "Pontus explains", IsPublished: true, LastUpdateDate: "2022-06-15 08:00:00"}
+	to := from
+	to.Title = "Pontus Jansson explains"
+	to.IsPublished = false
+	to.LastUpdateDate = "2022-06-16 08:00:00"
+
+	assert.Empty(t, Diff(from, from))
+	assert.Equal(t, []FieldChange{
+		{Field: "title", From: "Pontus explains", To: "Pontus Jansson explains"},
+		{Field: "is_published", From: "true", To: "false"},
+		{Field: "last_update_date", From: "2022-06-15 08:00:00", To: "2022-06-16 08:00:00"},
+	}, Diff(from, to))
+}
@@ -0,0 +1,48 @@
+package internal
+
+import "time"
+
+// Withdrawal is the latest list of articles of a provider, used to find
+// the stored articles the club pulled. Articles of the provider published
+// since the oldest listed one that are not Listed are marked deleted, and
+// the Unpublished ones are marked unpublished. Older articles are left
+// alone, they only dropped out of the window of the list.
+type Withdrawal struct {
+	Provider    string
+	Listed      []string
+	Unpublished []string
+	Since       time.Time
+	At          time.Time
+}
+
+// WithdrawResult counts the articles changed by Storage.Withdraw.
+type WithdrawResult struct {
+	Deleted     int
+	Unpublished int
+}
+
+// IsListed reports whether the article with the given ID is in the list.
+func (w Withdrawal) IsListed(articleID string) bool {
+	return contains(w.Listed, articleID)
+}
+
+// IsUnpublished reports whether the list flags the article as unpublished.
+func (w Withdrawal) IsUnpublished(articleID string) bool {
+	return contains(w.Unpublished, articleID)
+}
+
+// Covers reports whether the article belongs to the window of the list,
+// so that its absence from it means the club pulled it.
+func (w Withdrawal) Covers(article ArticleNews) bool {
+	return article.Provider == w.Provider && !article.PublishDate.Before(w.Since)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}